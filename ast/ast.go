@@ -2,7 +2,6 @@ package ast
 
 import (
 	"bytes"
-	"fmt"
 	"strings"
 
 	"github.com/elsonwu/monkey-go/token"
@@ -46,16 +45,21 @@ func (p *Program) TokenLiteral() string {
 }
 
 type LetStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token   token.Token
+	Name    *Identifier
+	Pattern Pattern // set instead of Name when destructuring, as in let [a, b] = pair;
+	Value   Expression
 }
 
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -147,6 +151,19 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+func (fl *FloatLiteral) String() string {
+	return fl.Token.Literal
+}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -344,6 +361,32 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+type TupleLiteral struct {
+	Token    token.Token // the ( token
+	Elements []Expression
+}
+
+func (tl *TupleLiteral) expressionNode() {}
+func (tl *TupleLiteral) TokenLiteral() string {
+	return tl.Token.Literal
+}
+
+func (tl *TupleLiteral) String() string {
+	var out bytes.Buffer
+	elms := []string{}
+	for _, e := range tl.Elements {
+		elms = append(elms, e.String())
+	}
+	out.WriteString("(")
+	out.WriteString(strings.Join(elms, ", "))
+	if len(elms) == 1 {
+		out.WriteString(",")
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
 type IndexExpression struct {
 	Token token.Token // the [ token
 	Left  Expression
@@ -365,9 +408,38 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+type SliceExpression struct {
+	Token token.Token // the [ token
+	Left  Expression
+	Start Expression // nil when omitted, as in arr[:2]
+	End   Expression // nil when omitted, as in arr[1:]
+}
+
+func (se *SliceExpression) expressionNode() {}
+func (se *SliceExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("]")
+
+	return out.String()
+}
+
 type HashLiteral struct {
 	Token token.Token // the {
 	Pairs map[Expression]Expression
+	Keys  []Expression // the keys of Pairs in source order
 }
 
 func (hl *HashLiteral) expressionNode() {}
@@ -379,8 +451,8 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for k, v := range hl.Pairs {
-		pairs = append(pairs, fmt.Sprintf(k.String()+":"+v.String()))
+	for _, k := range hl.Keys {
+		pairs = append(pairs, k.String()+":"+hl.Pairs[k].String())
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(pairs, ", "))
@@ -388,3 +460,459 @@ func (hl *HashLiteral) String() string {
 
 	return out.String()
 }
+
+type AssignExpression struct {
+	Token    token.Token // the = or compound assignment token
+	Target   Expression  // an *Identifier or *IndexExpression
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode() {}
+func (ae *AssignExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ae.Target.String())
+	out.WriteString(" " + ae.Operator + " ")
+	out.WriteString(ae.Value.String())
+
+	return out.String()
+}
+
+type WhileExpression struct {
+	Token     token.Token // the while token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (we *WhileExpression) expressionNode() {}
+func (we *WhileExpression) TokenLiteral() string {
+	return we.Token.Literal
+}
+
+func (we *WhileExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while")
+	out.WriteString(we.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(we.Body.String())
+
+	return out.String()
+}
+
+type ForExpression struct {
+	Token    token.Token // the for token
+	Key      *Identifier // optional, the index or hash key
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fe *ForExpression) expressionNode() {}
+func (fe *ForExpression) TokenLiteral() string {
+	return fe.Token.Literal
+}
+
+func (fe *ForExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for(")
+	if fe.Key != nil {
+		out.WriteString(fe.Key.String())
+		out.WriteString(", ")
+	}
+	out.WriteString(fe.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fe.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fe.Body.String())
+
+	return out.String()
+}
+
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (bs *BreakStatement) statementNode() {}
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs *BreakStatement) String() string {
+	return bs.Token.Literal + ";"
+}
+
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func (cs *ContinueStatement) statementNode() {}
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+func (cs *ContinueStatement) String() string {
+	return cs.Token.Literal + ";"
+}
+
+// ImportStatement loads another file as a module, as in import "lib/util".
+type ImportStatement struct {
+	Token token.Token // the import token
+	Path  string
+}
+
+func (is *ImportStatement) statementNode() {}
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+func (is *ImportStatement) String() string {
+	return is.Token.Literal + " \"" + is.Path + "\";"
+}
+
+// MemberExpression accesses a named member, as in util.name, or as in
+// config?.port when Optional.
+type MemberExpression struct {
+	Token    token.Token // the . or ?. token
+	Left     Expression
+	Member   *Identifier
+	Optional bool
+}
+
+func (me *MemberExpression) expressionNode() {}
+func (me *MemberExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+func (me *MemberExpression) String() string {
+	return me.Left.String() + me.Token.Literal + me.Member.String()
+}
+
+// StructStatement declares a record type, as in struct Point { x, y }.
+type StructStatement struct {
+	Token  token.Token // the struct token
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ss *StructStatement) statementNode() {}
+func (ss *StructStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+
+func (ss *StructStatement) String() string {
+	fields := []string{}
+	for _, f := range ss.Fields {
+		fields = append(fields, f.String())
+	}
+
+	return ss.Token.Literal + " " + ss.Name.String() + " { " + strings.Join(fields, ", ") + " }"
+}
+
+// ClassStatement declares a class, as in
+// class Dog extends Animal { init(name) { self.name = name } }.
+type ClassStatement struct {
+	Token      token.Token // the class token
+	Name       *Identifier
+	Superclass *Identifier // nil without extends
+	Methods    []*ClassMethod
+}
+
+// ClassMethod is one method in a class body. Its function's token is the
+// method name.
+type ClassMethod struct {
+	Name     *Identifier
+	Function *FunctionLiteral
+}
+
+func (cs *ClassStatement) statementNode() {}
+func (cs *ClassStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+func (cs *ClassStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(cs.Token.Literal + " " + cs.Name.String())
+	if cs.Superclass != nil {
+		out.WriteString(" extends " + cs.Superclass.String())
+	}
+
+	out.WriteString(" { ")
+	for _, m := range cs.Methods {
+		out.WriteString(m.Function.String() + " ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// EnumStatement declares an enumeration, as in enum Color { Red, Blue(value) }.
+type EnumStatement struct {
+	Token    token.Token // the enum token
+	Name     *Identifier
+	Variants []*EnumVariant
+}
+
+// EnumVariant is one variant of an enum along with the names of the values
+// it carries, if any.
+type EnumVariant struct {
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (es *EnumStatement) statementNode() {}
+func (es *EnumStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+func (es *EnumStatement) String() string {
+	variants := []string{}
+	for _, v := range es.Variants {
+		variants = append(variants, v.String())
+	}
+
+	return es.Token.Literal + " " + es.Name.String() + " { " + strings.Join(variants, ", ") + " }"
+}
+
+func (ev *EnumVariant) String() string {
+	if len(ev.Fields) == 0 {
+		return ev.Name.String()
+	}
+
+	fields := []string{}
+	for _, f := range ev.Fields {
+		fields = append(fields, f.String())
+	}
+
+	return ev.Name.String() + "(" + strings.Join(fields, ", ") + ")"
+}
+
+// MatchExpression evaluates the body of the first arm whose pattern matches
+// the subject, as in match (x) { 0 => "zero", n if n > 0 => "positive" }.
+type MatchExpression struct {
+	Token   token.Token // the match token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+// MatchArm is one pattern => body arm of a match. Guard is nil when the
+// arm has no if clause.
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression
+	Body    *BlockStatement
+}
+
+func (me *MatchExpression) expressionNode() {}
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+func (me *MatchExpression) String() string {
+	arms := []string{}
+	for _, a := range me.Arms {
+		arm := a.Pattern.String()
+		if a.Guard != nil {
+			arm += " if " + a.Guard.String()
+		}
+		arms = append(arms, arm+" => "+a.Body.String())
+	}
+
+	return me.Token.Literal + " (" + me.Subject.String() + ") { " + strings.Join(arms, ", ") + " }"
+}
+
+// Pattern describes the shape a value must have to match, binding names to
+// the parts it takes apart.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// WildcardPattern is _, which matches anything and binds nothing.
+type WildcardPattern struct {
+	Token token.Token
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) String() string       { return wp.Token.Literal }
+
+// LiteralPattern matches values equal to a number, string or boolean literal.
+type LiteralPattern struct {
+	Token token.Token
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Token.Literal }
+func (lp *LiteralPattern) String() string       { return lp.Value.String() }
+
+// BindingPattern matches anything and binds it to Name.
+type BindingPattern struct {
+	Name *Identifier
+}
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Name.TokenLiteral() }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+// ArrayPattern matches arrays and tuples element by element, as in
+// [first, second, ...rest]. Without Rest the lengths must be equal.
+type ArrayPattern struct {
+	Token    token.Token // the [ token
+	Elements []Pattern
+	Rest     Pattern // a BindingPattern or WildcardPattern, or nil
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, e := range ap.Elements {
+		elements = append(elements, e.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// HashPattern matches hashes holding every listed key, as in
+// {name, age: years}. Other keys are ignored.
+type HashPattern struct {
+	Token  token.Token // the { token
+	Keys   []string
+	Values []Pattern
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	pairs := []string{}
+	for i, k := range hp.Keys {
+		pairs = append(pairs, k+": "+hp.Values[i].String())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// VariantPattern matches a value of an enum variant, as in Color.Blue(v).
+// Args is nil when the pattern has no parentheses, in which case the
+// variant's values are not looked at.
+type VariantPattern struct {
+	Token   token.Token // the enum name
+	Enum    *Identifier
+	Variant *Identifier
+	Args    []Pattern
+}
+
+func (vp *VariantPattern) patternNode()         {}
+func (vp *VariantPattern) TokenLiteral() string { return vp.Token.Literal }
+func (vp *VariantPattern) String() string {
+	name := vp.Enum.String() + "." + vp.Variant.String()
+	if vp.Args == nil {
+		return name
+	}
+
+	args := []string{}
+	for _, a := range vp.Args {
+		args = append(args, a.String())
+	}
+
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// ThrowStatement raises its value as an error, as in throw "bad input";.
+type ThrowStatement struct {
+	Token token.Token // the throw token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode() {}
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+func (ts *ThrowStatement) String() string {
+	return ts.Token.Literal + " " + ts.Value.String() + ";"
+}
+
+// TryExpression runs Block, handing any error it raises to Catch, and runs
+// Finally afterwards either way. Either Catch or Finally may be nil, and
+// Param is nil when the catch clause does not name the error.
+type TryExpression struct {
+	Token   token.Token // the try token
+	Block   *BlockStatement
+	Param   *Identifier
+	Catch   *BlockStatement
+	Finally *BlockStatement
+}
+
+func (te *TryExpression) expressionNode() {}
+func (te *TryExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try " + te.Block.String())
+	if te.Catch != nil {
+		out.WriteString(" catch")
+		if te.Param != nil {
+			out.WriteString(" (" + te.Param.String() + ")")
+		}
+		out.WriteString(" " + te.Catch.String())
+	}
+	if te.Finally != nil {
+		out.WriteString(" finally " + te.Finally.String())
+	}
+
+	return out.String()
+}
+
+// PropagateExpression is the postfix ? operator, which unwraps an ok result
+// and returns an err result from the enclosing function, as in parse(s)?.
+type PropagateExpression struct {
+	Token token.Token // the ? token
+	Value Expression
+}
+
+func (pe *PropagateExpression) expressionNode() {}
+func (pe *PropagateExpression) TokenLiteral() string {
+	return pe.Token.Literal
+}
+
+func (pe *PropagateExpression) String() string {
+	return "(" + pe.Value.String() + "?)"
+}
+
+// MacroLiteral is macro(params) { body }. Bound with a top-level let, it
+// defines a macro that is expanded before the program runs.
+type MacroLiteral struct {
+	Token      token.Token // the macro token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (ml *MacroLiteral) expressionNode() {}
+func (ml *MacroLiteral) TokenLiteral() string {
+	return ml.Token.Literal
+}
+
+func (ml *MacroLiteral) String() string {
+	params := []string{}
+	for _, p := range ml.Parameters {
+		params = append(params, p.String())
+	}
+
+	return ml.TokenLiteral() + "(" + strings.Join(params, ", ") + ") " + ml.Body.String()
+}
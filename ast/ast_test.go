@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/elsonwu/monkey-go/token"
@@ -27,3 +28,138 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong, got=%q", program.String())
 	}
 }
+
+func TestModify(t *testing.T) {
+	one := func() Expression { return &IntegerLiteral{Value: 1} }
+	two := func() Expression { return &IntegerLiteral{Value: 2} }
+	oneBlock := func() *BlockStatement {
+		return &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}}
+	}
+	twoBlock := func() *BlockStatement {
+		return &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}}
+	}
+
+	turnOneIntoTwo := func(node Node) Node {
+		integer, ok := node.(*IntegerLiteral)
+		if !ok {
+			return node
+		}
+
+		if integer.Value != 1 {
+			return node
+		}
+
+		integer.Value = 2
+		return integer
+	}
+
+	tests := []struct {
+		input    Node
+		expected Node
+	}{
+		{one(), two()},
+		{
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+		},
+		{&InfixExpression{Left: one(), Operator: "+", Right: two()}, &InfixExpression{Left: two(), Operator: "+", Right: two()}},
+		{&InfixExpression{Left: two(), Operator: "+", Right: one()}, &InfixExpression{Left: two(), Operator: "+", Right: two()}},
+		{&PrefixExpression{Operator: "-", Right: one()}, &PrefixExpression{Operator: "-", Right: two()}},
+		{&PropagateExpression{Value: one()}, &PropagateExpression{Value: two()}},
+		{&IndexExpression{Left: one(), Index: one()}, &IndexExpression{Left: two(), Index: two()}},
+		{&SliceExpression{Left: one(), Start: one()}, &SliceExpression{Left: two(), Start: two()}},
+		{&MemberExpression{Left: one(), Member: &Identifier{Value: "x"}}, &MemberExpression{Left: two(), Member: &Identifier{Value: "x"}}},
+		{&AssignExpression{Target: &Identifier{Value: "x"}, Operator: "=", Value: one()}, &AssignExpression{Target: &Identifier{Value: "x"}, Operator: "=", Value: two()}},
+		{&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{one(), one()}}, &CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{two(), two()}}},
+		{
+			&IfExpression{Condition: one(), Consequence: oneBlock(), Alternative: oneBlock()},
+			&IfExpression{Condition: two(), Consequence: twoBlock(), Alternative: twoBlock()},
+		},
+		{&WhileExpression{Condition: one(), Body: oneBlock()}, &WhileExpression{Condition: two(), Body: twoBlock()}},
+		{
+			&ForExpression{Value: &Identifier{Value: "x"}, Iterable: one(), Body: oneBlock()},
+			&ForExpression{Value: &Identifier{Value: "x"}, Iterable: two(), Body: twoBlock()},
+		},
+		{
+			&MatchExpression{Subject: one(), Arms: []*MatchArm{{Pattern: &WildcardPattern{}, Guard: one(), Body: oneBlock()}}},
+			&MatchExpression{Subject: two(), Arms: []*MatchArm{{Pattern: &WildcardPattern{}, Guard: two(), Body: twoBlock()}}},
+		},
+		{
+			&TryExpression{Block: oneBlock(), Catch: oneBlock(), Finally: oneBlock()},
+			&TryExpression{Block: twoBlock(), Catch: twoBlock(), Finally: twoBlock()},
+		},
+		{&ReturnStatement{ReturnValue: one()}, &ReturnStatement{ReturnValue: two()}},
+		{&ThrowStatement{Value: one()}, &ThrowStatement{Value: two()}},
+		{&LetStatement{Name: &Identifier{Value: "x"}, Value: one()}, &LetStatement{Name: &Identifier{Value: "x"}, Value: two()}},
+		{&FunctionLiteral{Parameters: []*Identifier{}, Body: oneBlock()}, &FunctionLiteral{Parameters: []*Identifier{}, Body: twoBlock()}},
+		{&MacroLiteral{Parameters: []*Identifier{}, Body: oneBlock()}, &MacroLiteral{Parameters: []*Identifier{}, Body: twoBlock()}},
+		{
+			&ClassStatement{Name: &Identifier{Value: "A"}, Methods: []*ClassMethod{{Name: &Identifier{Value: "f"}, Function: &FunctionLiteral{Body: oneBlock()}}}},
+			&ClassStatement{Name: &Identifier{Value: "A"}, Methods: []*ClassMethod{{Name: &Identifier{Value: "f"}, Function: &FunctionLiteral{Body: twoBlock()}}}},
+		},
+		{&ArrayLiteral{Elements: []Expression{one(), one()}}, &ArrayLiteral{Elements: []Expression{two(), two()}}},
+		{&TupleLiteral{Elements: []Expression{one(), one()}}, &TupleLiteral{Elements: []Expression{two(), two()}}},
+	}
+
+	for _, tt := range tests {
+		modified := Modify(tt.input, turnOneIntoTwo)
+
+		if !reflect.DeepEqual(modified, tt.expected) {
+			t.Errorf("not equal. got=%#v, want=%#v", modified, tt.expected)
+		}
+	}
+
+	key := one()
+	hashLiteral := &HashLiteral{
+		Pairs: map[Expression]Expression{key: one()},
+		Keys:  []Expression{key},
+	}
+
+	Modify(hashLiteral, turnOneIntoTwo)
+
+	for _, key := range hashLiteral.Keys {
+		if key.(*IntegerLiteral).Value != 2 {
+			t.Errorf("key is not 2, got=%d", key.(*IntegerLiteral).Value)
+		}
+
+		val := hashLiteral.Pairs[key]
+		if val.(*IntegerLiteral).Value != 2 {
+			t.Errorf("value is not 2, got=%d", val.(*IntegerLiteral).Value)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	key := &StringLiteral{Value: "k"}
+	original := &InfixExpression{Left: &IntegerLiteral{Value: 1}, Operator: "+", Right: &HashLiteral{
+		Pairs: map[Expression]Expression{key: &IntegerLiteral{Value: 1}},
+		Keys:  []Expression{key},
+	}}
+
+	copied, ok := Copy(original).(*InfixExpression)
+	if !ok || copied == original {
+		t.Fatalf("Copy did not return a new *InfixExpression. got=%#v", copied)
+	}
+
+	Modify(copied, func(node Node) Node {
+		if integer, ok := node.(*IntegerLiteral); ok {
+			integer.Value = 2
+		}
+		return node
+	})
+
+	if original.Left.(*IntegerLiteral).Value != 1 || original.Right.(*HashLiteral).Pairs[key].(*IntegerLiteral).Value != 1 {
+		t.Errorf("original changed by modifying the copy")
+	}
+	if copied.Left.(*IntegerLiteral).Value != 2 {
+		t.Errorf("copy not modified, got=%d", copied.Left.(*IntegerLiteral).Value)
+	}
+
+	hash := copied.Right.(*HashLiteral)
+	if hash.Keys[0] == Expression(key) {
+		t.Errorf("hash key not copied")
+	}
+	if value, ok := hash.Pairs[hash.Keys[0]]; !ok || value.(*IntegerLiteral).Value != 2 {
+		t.Errorf("hash keys no longer shared with pairs")
+	}
+}
@@ -0,0 +1,75 @@
+package ast
+
+import "reflect"
+
+// Copy returns a deep copy of the tree under node, so that Modify can
+// rewrite the copy and leave the original as it was. Nodes reachable along
+// more than one path, such as the keys of a HashLiteral, stay shared in the
+// copy.
+func Copy(node Node) Node {
+	if node == nil {
+		return nil
+	}
+
+	copied := copyValue(reflect.ValueOf(node), make(map[interface{}]reflect.Value))
+	return copied.Interface().(Node)
+}
+
+func copyValue(v reflect.Value, seen map[interface{}]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := seen[v.Interface()]; ok {
+			return c
+		}
+
+		c := reflect.New(v.Elem().Type())
+		seen[v.Interface()] = c
+		c.Elem().Set(copyValue(v.Elem(), seen))
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(copyValue(v.Elem(), seen))
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			c.Field(i).Set(copyValue(v.Field(i), seen))
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i), seen))
+		}
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(copyValue(iter.Key(), seen), copyValue(iter.Value(), seen))
+		}
+		return c
+
+	default:
+		return v
+	}
+}
@@ -0,0 +1,145 @@
+package ast
+
+// ModifierFunc is applied to every node by Modify and returns the node to
+// put in its place.
+type ModifierFunc func(Node) Node
+
+// Modify walks the tree under node depth-first, rebuilding each node from
+// its modified children before handing it to modifier.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch node := node.(type) {
+	case *Program:
+		for i, statement := range node.Statements {
+			node.Statements[i], _ = Modify(statement, modifier).(Statement)
+		}
+
+	case *ExpressionStatement:
+		node.Expression, _ = Modify(node.Expression, modifier).(Expression)
+
+	case *LetStatement:
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	case *ReturnStatement:
+		if node.ReturnValue != nil {
+			node.ReturnValue, _ = Modify(node.ReturnValue, modifier).(Expression)
+		}
+
+	case *ThrowStatement:
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	case *BlockStatement:
+		for i, statement := range node.Statements {
+			node.Statements[i], _ = Modify(statement, modifier).(Statement)
+		}
+
+	case *InfixExpression:
+		node.Left, _ = Modify(node.Left, modifier).(Expression)
+		node.Right, _ = Modify(node.Right, modifier).(Expression)
+
+	case *PrefixExpression:
+		node.Right, _ = Modify(node.Right, modifier).(Expression)
+
+	case *PropagateExpression:
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	case *IndexExpression:
+		node.Left, _ = Modify(node.Left, modifier).(Expression)
+		node.Index, _ = Modify(node.Index, modifier).(Expression)
+
+	case *SliceExpression:
+		node.Left, _ = Modify(node.Left, modifier).(Expression)
+		if node.Start != nil {
+			node.Start, _ = Modify(node.Start, modifier).(Expression)
+		}
+		if node.End != nil {
+			node.End, _ = Modify(node.End, modifier).(Expression)
+		}
+
+	case *MemberExpression:
+		node.Left, _ = Modify(node.Left, modifier).(Expression)
+
+	case *AssignExpression:
+		node.Target, _ = Modify(node.Target, modifier).(Expression)
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	case *CallExpression:
+		node.Function, _ = Modify(node.Function, modifier).(Expression)
+		for i, arg := range node.Arguments {
+			node.Arguments[i], _ = Modify(arg, modifier).(Expression)
+		}
+
+	case *IfExpression:
+		node.Condition, _ = Modify(node.Condition, modifier).(Expression)
+		node.Consequence, _ = Modify(node.Consequence, modifier).(*BlockStatement)
+		if node.Alternative != nil {
+			node.Alternative, _ = Modify(node.Alternative, modifier).(*BlockStatement)
+		}
+
+	case *WhileExpression:
+		node.Condition, _ = Modify(node.Condition, modifier).(Expression)
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	case *ForExpression:
+		node.Iterable, _ = Modify(node.Iterable, modifier).(Expression)
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	case *MatchExpression:
+		node.Subject, _ = Modify(node.Subject, modifier).(Expression)
+		for _, arm := range node.Arms {
+			if arm.Guard != nil {
+				arm.Guard, _ = Modify(arm.Guard, modifier).(Expression)
+			}
+			arm.Body, _ = Modify(arm.Body, modifier).(*BlockStatement)
+		}
+
+	case *TryExpression:
+		node.Block, _ = Modify(node.Block, modifier).(*BlockStatement)
+		if node.Catch != nil {
+			node.Catch, _ = Modify(node.Catch, modifier).(*BlockStatement)
+		}
+		if node.Finally != nil {
+			node.Finally, _ = Modify(node.Finally, modifier).(*BlockStatement)
+		}
+
+	case *FunctionLiteral:
+		for i, param := range node.Parameters {
+			node.Parameters[i], _ = Modify(param, modifier).(*Identifier)
+		}
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	case *MacroLiteral:
+		for i, param := range node.Parameters {
+			node.Parameters[i], _ = Modify(param, modifier).(*Identifier)
+		}
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	case *ClassStatement:
+		for _, method := range node.Methods {
+			method.Function, _ = Modify(method.Function, modifier).(*FunctionLiteral)
+		}
+
+	case *ArrayLiteral:
+		for i, element := range node.Elements {
+			node.Elements[i], _ = Modify(element, modifier).(Expression)
+		}
+
+	case *TupleLiteral:
+		for i, element := range node.Elements {
+			node.Elements[i], _ = Modify(element, modifier).(Expression)
+		}
+
+	case *HashLiteral:
+		pairs := make(map[Expression]Expression, len(node.Pairs))
+		keys := make([]Expression, 0, len(node.Keys))
+		for _, key := range node.Keys {
+			newKey, _ := Modify(key, modifier).(Expression)
+			newValue, _ := Modify(node.Pairs[key], modifier).(Expression)
+			pairs[newKey] = newValue
+			keys = append(keys, newKey)
+		}
+		node.Pairs = pairs
+		node.Keys = keys
+	}
+
+	return modifier(node)
+}
@@ -2,36 +2,44 @@ package evaluator
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/elsonwu/monkey-go/object"
 )
 
+// modules holds the builtin library namespaces, such as math.
+var modules = map[string]*object.Module{}
+
 var builtins = map[string]*object.Builtin{
 	"len": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
 			if len(args) > 1 {
-				return newError("wrong number of arguments, got=%d, want=1", len(args))
+				return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
 			}
 
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Tuple:
+				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Range:
+				return &object.Integer{Value: arg.Len()}
 			default:
-				return newError("argument to `len` not supported, got %s", args[0].Type())
+				return newArgumentError("argument to `len` not supported, got %s", args[0].Type())
 			}
 		},
 	},
 	"first": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
 			if len(args) > 1 {
-				return newError("wrong number of arguments, got=%d, want=1", len(args))
+				return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
 			}
 
 			arg, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("argument to `first` not supported, got %s", args[0].Type())
+				return newArgumentError("argument to `first` not supported, got %s", args[0].Type())
 			}
 
 			if len(arg.Elements) > 0 {
@@ -42,14 +50,14 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"rest": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
 			if len(args) > 1 {
-				return newError("wrong number of arguments, got=%d, want=1", len(args))
+				return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
 			}
 
 			arg, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("argument to `rest` not supported, got %s", args[0].Type())
+				return newArgumentError("argument to `rest` not supported, got %s", args[0].Type())
 			}
 
 			l := len(arg.Elements)
@@ -63,14 +71,14 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"last": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
 			if len(args) > 1 {
-				return newError("wrong number of arguments, got=%d, want=1", len(args))
+				return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
 			}
 
 			arg, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("argument to `last` not supported, got %s", args[0].Type())
+				return newArgumentError("argument to `last` not supported, got %s", args[0].Type())
 			}
 
 			l := len(arg.Elements)
@@ -82,14 +90,14 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"push": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
 			if len(args) < 2 {
-				return newError("wrong number of arguments, got=%d, want=2+", len(args))
+				return newArgumentError("wrong number of arguments, got=%d, want=2+", len(args))
 			}
 
 			arg, ok := args[0].(*object.Array)
 			if !ok {
-				return newError("argument to `push` not supported, want ARRAY, got %s", args[0].Type())
+				return newArgumentError("argument to `push` not supported, want ARRAY, got %s", args[0].Type())
 			}
 
 			l := len(arg.Elements)
@@ -100,13 +108,13 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"puts": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
 			if len(args) < 1 {
-				return newError("wrong number of arguments, got=%d, want=1+", len(args))
+				return newArgumentError("wrong number of arguments, got=%d, want=1+", len(args))
 			}
 
 			for _, arg := range args {
-				fmt.Println(arg.Inspect())
+				fmt.Fprintln(ctx.Stdout, arg.Inspect())
 			}
 
 			return NULL
@@ -0,0 +1,494 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+// The collection builtins call back into Monkey functions through
+// applyFunction, which itself depends on builtins, so they are registered
+// here rather than in the builtins literal to avoid an initialization cycle.
+func init() {
+	builtins["map"] = &object.Builtin{Fn: builtinMap}
+	builtins["filter"] = &object.Builtin{Fn: builtinFilter}
+	builtins["reduce"] = &object.Builtin{Fn: builtinReduce}
+	builtins["find"] = &object.Builtin{Fn: builtinFind}
+	builtins["any"] = &object.Builtin{Fn: builtinAny}
+	builtins["all"] = &object.Builtin{Fn: builtinAll}
+	builtins["sort"] = &object.Builtin{Fn: builtinSort}
+	builtins["zip"] = &object.Builtin{Fn: builtinZip}
+	builtins["flatten"] = &object.Builtin{Fn: builtinFlatten}
+	builtins["uniq"] = &object.Builtin{Fn: builtinUniq}
+	builtins["range"] = &object.Builtin{Fn: builtinRange}
+	builtins["tuple"] = &object.Builtin{Fn: builtinTuple}
+}
+
+// MaxArrayLength is the most elements the collection builtins produce from
+// a range or from range(), whose lengths the script controls.
+const MaxArrayLength = 1 << 26
+
+func checkArrayLength(name string, n uint64) *object.Error {
+	if n > MaxArrayLength {
+		return newArgumentError("argument to `%s` not supported, %d elements exceed the limit of %d", name, n, MaxArrayLength)
+	}
+
+	return nil
+}
+
+// elementsOf returns the elements of an array, tuple, range or string so the
+// collection builtins accept any of them.
+func elementsOf(name string, obj object.Object) ([]object.Object, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Array:
+		return obj.Elements, nil
+	case *object.Tuple:
+		return obj.Elements, nil
+	case *object.Range, *object.String:
+		if r, ok := obj.(*object.Range); ok {
+			if err := checkArrayLength(name, uint64(r.Len())); err != nil {
+				return nil, err
+			}
+		}
+
+		elements := []object.Object{}
+		forEach(obj, func(_, value object.Object) bool {
+			elements = append(elements, value)
+			return true
+		})
+		return elements, nil
+	default:
+		return nil, newArgumentError("argument to `%s` not supported, want ARRAY, got %s", name, obj.Type())
+	}
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin, *object.Struct, *object.Class, *object.Variant:
+		return true
+	}
+
+	return false
+}
+
+// collectionArgs validates the common (collection, fn) argument shape.
+func collectionArgs(name string, args []object.Object) ([]object.Object, object.Object, *object.Error) {
+	if len(args) != 2 {
+		return nil, nil, newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	elements, err := elementsOf(name, args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isCallable(args[1]) {
+		return nil, nil, newArgumentError("argument to `%s` not supported, want FUNCTION, got %s", name, args[1].Type())
+	}
+
+	return elements, args[1], nil
+}
+
+func builtinMap(ctx *object.Context, args ...object.Object) object.Object {
+	elements, fn, err := collectionArgs("map", args)
+	if err != nil {
+		return err
+	}
+
+	result := make([]object.Object, len(elements))
+	for i, e := range elements {
+		v := applyFunction(ctx, fn, []object.Object{e})
+		if isError(v) {
+			return v
+		}
+		result[i] = v
+	}
+
+	return &object.Array{Elements: result}
+}
+
+func builtinFilter(ctx *object.Context, args ...object.Object) object.Object {
+	elements, fn, err := collectionArgs("filter", args)
+	if err != nil {
+		return err
+	}
+
+	result := []object.Object{}
+	for _, e := range elements {
+		v := applyFunction(ctx, fn, []object.Object{e})
+		if isError(v) {
+			return v
+		}
+
+		if isTruth(v) {
+			result = append(result, e)
+		}
+	}
+
+	return &object.Array{Elements: result}
+}
+
+func builtinReduce(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2 or 3", len(args))
+	}
+
+	elements, fn, err := collectionArgs("reduce", args[:2])
+	if err != nil {
+		return err
+	}
+
+	var acc object.Object
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(elements) == 0 {
+			return NULL
+		}
+		acc, elements = elements[0], elements[1:]
+	}
+
+	for _, e := range elements {
+		acc = applyFunction(ctx, fn, []object.Object{acc, e})
+		if isError(acc) {
+			return acc
+		}
+	}
+
+	return acc
+}
+
+func builtinFind(ctx *object.Context, args ...object.Object) object.Object {
+	elements, fn, err := collectionArgs("find", args)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range elements {
+		v := applyFunction(ctx, fn, []object.Object{e})
+		if isError(v) {
+			return v
+		}
+
+		if isTruth(v) {
+			return e
+		}
+	}
+
+	return NULL
+}
+
+// predicateArgs validates (collection, fn?) for any and all; without a
+// function the elements' own truthiness is tested.
+func predicateArgs(name string, args []object.Object) ([]object.Object, object.Object, *object.Error) {
+	if len(args) == 1 {
+		elements, err := elementsOf(name, args[0])
+		return elements, nil, err
+	}
+
+	if len(args) != 2 {
+		return nil, nil, newArgumentError("wrong number of arguments, got=%d, want=1 or 2", len(args))
+	}
+
+	return collectionArgs(name, args)
+}
+
+func testElement(ctx *object.Context, fn object.Object, e object.Object) object.Object {
+	if fn == nil {
+		return e
+	}
+
+	return applyFunction(ctx, fn, []object.Object{e})
+}
+
+func builtinAny(ctx *object.Context, args ...object.Object) object.Object {
+	elements, fn, err := predicateArgs("any", args)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range elements {
+		v := testElement(ctx, fn, e)
+		if isError(v) {
+			return v
+		}
+
+		if isTruth(v) {
+			return TRUE
+		}
+	}
+
+	return FALSE
+}
+
+func builtinAll(ctx *object.Context, args ...object.Object) object.Object {
+	elements, fn, err := predicateArgs("all", args)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range elements {
+		v := testElement(ctx, fn, e)
+		if isError(v) {
+			return v
+		}
+
+		if !isTruth(v) {
+			return FALSE
+		}
+	}
+
+	return TRUE
+}
+
+func builtinSort(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1 or 2", len(args))
+	}
+
+	elements, err := elementsOf("sort", args[0])
+	if err != nil {
+		return err
+	}
+
+	sorted := make([]object.Object, len(elements))
+	copy(sorted, elements)
+
+	if len(args) == 2 {
+		if !isCallable(args[1]) {
+			return newArgumentError("argument to `sort` not supported, want FUNCTION, got %s", args[1].Type())
+		}
+
+		var sortErr object.Object
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if sortErr != nil {
+				return false
+			}
+
+			less, err := compareWith(ctx, args[1], sorted[i], sorted[j])
+			if err != nil {
+				sortErr = err
+			}
+			return less
+		})
+		if sortErr != nil {
+			return sortErr
+		}
+
+		return &object.Array{Elements: sorted}
+	}
+
+	// numbers order among themselves, integers and floats alike, and
+	// strings among themselves
+	for _, e := range sorted {
+		if !isNumber(e) && e.Type() != object.STRING_OBJ {
+			return newArgumentError("argument to `sort` not supported, cannot order %s", e.Type())
+		}
+
+		if isNumber(e) != isNumber(sorted[0]) {
+			return newArgumentError("argument to `sort` not supported, mixed %s and %s", sorted[0].Type(), e.Type())
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		switch a := sorted[i].(type) {
+		case *object.Integer:
+			if b, ok := sorted[j].(*object.Integer); ok {
+				return a.Value < b.Value
+			}
+		case *object.String:
+			return a.Value < sorted[j].(*object.String).Value
+		}
+		return toFloat(sorted[i]) < toFloat(sorted[j])
+	})
+
+	return &object.Array{Elements: sorted}
+}
+
+// compareWith calls a user comparator, which may answer either with a
+// boolean "a comes before b" or with a negative, zero or positive integer.
+func compareWith(ctx *object.Context, fn object.Object, a, b object.Object) (bool, object.Object) {
+	v := applyFunction(ctx, fn, []object.Object{a, b})
+	switch v := v.(type) {
+	case *object.Boolean:
+		return v.Value, nil
+	case *object.Integer:
+		return v.Value < 0, nil
+	case *object.Error:
+		return false, v
+	default:
+		return false, newError("comparator for `sort` must return BOOLEAN or INTEGER, got %s", v.Type())
+	}
+}
+
+func builtinZip(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) < 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2+", len(args))
+	}
+
+	lists := make([][]object.Object, len(args))
+	shortest := -1
+	for i, arg := range args {
+		elements, err := elementsOf("zip", arg)
+		if err != nil {
+			return err
+		}
+
+		lists[i] = elements
+		if shortest < 0 || len(elements) < shortest {
+			shortest = len(elements)
+		}
+	}
+
+	result := make([]object.Object, shortest)
+	for i := range result {
+		row := make([]object.Object, len(lists))
+		for j, list := range lists {
+			row[j] = list[i]
+		}
+		result[i] = &object.Array{Elements: row}
+	}
+
+	return &object.Array{Elements: result}
+}
+
+func builtinFlatten(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1 or 2", len(args))
+	}
+
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newArgumentError("argument to `flatten` not supported, want ARRAY, got %s", args[0].Type())
+	}
+
+	depth := int64(1)
+	if len(args) == 2 {
+		d, ok := args[1].(*object.Integer)
+		if !ok {
+			return newArgumentError("argument to `flatten` not supported, want INTEGER, got %s", args[1].Type())
+		}
+		depth = d.Value
+	}
+
+	return &object.Array{Elements: flatten(arr.Elements, depth)}
+}
+
+func flatten(elements []object.Object, depth int64) []object.Object {
+	result := []object.Object{}
+	for _, e := range elements {
+		if inner, ok := e.(*object.Array); ok && depth > 0 {
+			result = append(result, flatten(inner.Elements, depth-1)...)
+			continue
+		}
+		result = append(result, e)
+	}
+
+	return result
+}
+
+func builtinUniq(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	elements, err := elementsOf("uniq", args[0])
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[object.HashKey]bool)
+	result := []object.Object{}
+	for _, e := range elements {
+		if key, ok := object.HashKeyOf(e); ok {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		} else if containsEqual(result, e) {
+			continue
+		}
+
+		result = append(result, e)
+	}
+
+	return &object.Array{Elements: result}
+}
+
+func containsEqual(elements []object.Object, obj object.Object) bool {
+	for _, e := range elements {
+		if object.Equal(e, obj) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func builtinRange(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1 to 3", len(args))
+	}
+
+	bounds := make([]int64, len(args))
+	for i, arg := range args {
+		n, ok := arg.(*object.Integer)
+		if !ok {
+			return newArgumentError("argument to `range` not supported, want INTEGER, got %s", arg.Type())
+		}
+		bounds[i] = n.Value
+	}
+
+	start, end, step := int64(0), bounds[0], int64(1)
+	if len(bounds) > 1 {
+		start, end = bounds[0], bounds[1]
+	}
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+
+	if step == 0 {
+		return newArgumentError("argument to `range` not supported, step must not be 0")
+	}
+
+	// count in uint64 so that neither the span nor the steps wrap around
+	// near the ends of the int64 range
+	var span, stride uint64
+	if step > 0 && start < end {
+		span, stride = uint64(end)-uint64(start), uint64(step)
+	} else if step < 0 && start > end {
+		span, stride = uint64(start)-uint64(end), uint64(-step)
+	}
+
+	var n uint64
+	if span > 0 {
+		n = (span-1)/stride + 1
+	}
+
+	if err := checkArrayLength("range", n); err != nil {
+		return err
+	}
+
+	result := make([]object.Object, n)
+	for i := range result {
+		result[i] = &object.Integer{Value: int64(uint64(start) + uint64(i)*uint64(step))}
+	}
+
+	return &object.Array{Elements: result}
+}
+
+// builtinTuple freezes an array (or any other sequence) into a tuple.
+func builtinTuple(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	elements, err := elementsOf("tuple", args[0])
+	if err != nil {
+		return err
+	}
+
+	frozen := make([]object.Object, len(elements))
+	copy(frozen, elements)
+	return &object.Tuple{Elements: frozen}
+}
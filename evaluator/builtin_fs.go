@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	builtins["read_file"] = &object.Builtin{Fn: builtinReadFile}
+	builtins["write_file"] = &object.Builtin{Fn: builtinWriteFile}
+	builtins["list_dir"] = &object.Builtin{Fn: builtinListDir}
+	builtins["exists"] = &object.Builtin{Fn: builtinExists}
+	builtins["remove"] = &object.Builtin{Fn: builtinRemove}
+}
+
+func fileSystem(ctx *object.Context, name string) (object.FileSystem, *object.Error) {
+	if ctx.FS == nil {
+		return nil, newError("`%s` not available, no file system configured", name)
+	}
+
+	return ctx.FS, nil
+}
+
+func builtinReadFile(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("read_file", 1, args)
+	if err != nil {
+		return err
+	}
+
+	fsys, err := fileSystem(ctx, "read_file")
+	if err != nil {
+		return err
+	}
+
+	data, readErr := fsys.ReadFile(values[0])
+	if readErr != nil {
+		return newError("%s", readErr)
+	}
+
+	return &object.String{Value: string(data)}
+}
+
+func builtinWriteFile(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("write_file", 2, args)
+	if err != nil {
+		return err
+	}
+
+	fsys, err := fileSystem(ctx, "write_file")
+	if err != nil {
+		return err
+	}
+
+	if writeErr := fsys.WriteFile(values[0], []byte(values[1])); writeErr != nil {
+		return newError("%s", writeErr)
+	}
+
+	return NULL
+}
+
+// builtinListDir returns the sorted names in a directory, by default the
+// root.
+func builtinListDir(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) == 0 {
+		args = []object.Object{&object.String{Value: "."}}
+	}
+
+	values, err := stringArgs("list_dir", 1, args)
+	if err != nil {
+		return err
+	}
+
+	fsys, err := fileSystem(ctx, "list_dir")
+	if err != nil {
+		return err
+	}
+
+	names, readErr := fsys.ReadDir(values[0])
+	if readErr != nil {
+		return newError("%s", readErr)
+	}
+
+	return stringsToArray(names)
+}
+
+func builtinExists(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("exists", 1, args)
+	if err != nil {
+		return err
+	}
+
+	fsys, err := fileSystem(ctx, "exists")
+	if err != nil {
+		return err
+	}
+
+	ok, statErr := fsys.Exists(values[0])
+	if statErr != nil {
+		return newError("%s", statErr)
+	}
+
+	return nativeBoolToBooleanObject(ok)
+}
+
+func builtinRemove(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("remove", 1, args)
+	if err != nil {
+		return err
+	}
+
+	fsys, err := fileSystem(ctx, "remove")
+	if err != nil {
+		return err
+	}
+
+	if removeErr := fsys.Remove(values[0]); removeErr != nil {
+		return newError("%s", removeErr)
+	}
+
+	return NULL
+}
@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	builtins["keys"] = &object.Builtin{Fn: builtinKeys}
+	builtins["values"] = &object.Builtin{Fn: builtinValues}
+	builtins["has"] = &object.Builtin{Fn: builtinHas}
+	builtins["delete"] = &object.Builtin{Fn: builtinDelete}
+	builtins["merge"] = &object.Builtin{Fn: builtinMerge}
+}
+
+func hashArg(name string, arg object.Object) (*object.Hash, *object.Error) {
+	hash, ok := arg.(*object.Hash)
+	if !ok {
+		return nil, newArgumentError("argument to `%s` not supported, want HASH, got %s", name, arg.Type())
+	}
+
+	return hash, nil
+}
+
+func hashKeyArg(arg object.Object) (object.HashKey, *object.Error) {
+	key, ok := object.HashKeyOf(arg)
+	if !ok {
+		return object.HashKey{}, newTypeError("unusable as hash key: %s", arg.Type())
+	}
+
+	return key, nil
+}
+
+func builtinKeys(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	hash, err := hashArg("keys", args[0])
+	if err != nil {
+		return err
+	}
+
+	keys := []object.Object{}
+	for _, pair := range hash.Ordered() {
+		keys = append(keys, pair.Key)
+	}
+
+	return &object.Array{Elements: keys}
+}
+
+func builtinValues(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	hash, err := hashArg("values", args[0])
+	if err != nil {
+		return err
+	}
+
+	values := []object.Object{}
+	for _, pair := range hash.Ordered() {
+		values = append(values, pair.Value)
+	}
+
+	return &object.Array{Elements: values}
+}
+
+func builtinHas(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	hash, err := hashArg("has", args[0])
+	if err != nil {
+		return err
+	}
+
+	key, err := hashKeyArg(args[1])
+	if err != nil {
+		return err
+	}
+
+	_, ok := hash.Get(key)
+	return nativeBoolToBooleanObject(ok)
+}
+
+// builtinDelete removes a key from the hash in place and returns the value
+// it held, or null when the key was absent.
+func builtinDelete(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	hash, err := hashArg("delete", args[0])
+	if err != nil {
+		return err
+	}
+
+	key, err := hashKeyArg(args[1])
+	if err != nil {
+		return err
+	}
+
+	pair, ok := hash.Get(key)
+	if !ok {
+		return NULL
+	}
+
+	hash.Delete(key)
+	return pair.Value
+}
+
+// builtinMerge returns a new hash holding the pairs of every argument, with
+// later hashes overriding earlier ones.
+func builtinMerge(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1+", len(args))
+	}
+
+	merged := object.NewHash()
+	for _, arg := range args {
+		hash, err := hashArg("merge", arg)
+		if err != nil {
+			return err
+		}
+
+		for _, pair := range hash.Ordered() {
+			merged.Set(pair.Key.(object.Hashable).HashKey(), pair)
+		}
+	}
+
+	return merged
+}
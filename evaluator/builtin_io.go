@@ -0,0 +1,84 @@
+package evaluator
+
+import (
+	"io"
+	"strings"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	builtins["print"] = &object.Builtin{Fn: builtinPrint}
+	builtins["eprint"] = &object.Builtin{Fn: builtinEprint}
+	builtins["input"] = &object.Builtin{Fn: builtinInput}
+	builtins["readline"] = &object.Builtin{Fn: builtinReadline}
+}
+
+// writeValues writes its arguments separated by spaces, with strings
+// written as their raw text rather than quoted as puts shows them.
+func writeValues(w io.Writer, args []object.Object) {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if s, ok := arg.(*object.String); ok {
+			parts[i] = s.Value
+		} else {
+			parts[i] = arg.Inspect()
+		}
+	}
+
+	io.WriteString(w, strings.Join(parts, " "))
+}
+
+// builtinPrint writes to the context's stdout without a trailing newline.
+func builtinPrint(ctx *object.Context, args ...object.Object) object.Object {
+	writeValues(ctx.Stdout, args)
+	return NULL
+}
+
+// builtinEprint is print for the context's stderr.
+func builtinEprint(ctx *object.Context, args ...object.Object) object.Object {
+	writeValues(ctx.Stderr, args)
+	return NULL
+}
+
+// readLine reads the next line from the context's stdin without its line
+// ending, returning null once the input is exhausted.
+func readLine(ctx *object.Context) object.Object {
+	line, err := ctx.Stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return newError("input: %s", err)
+	}
+
+	if err == io.EOF && line == "" {
+		return NULL
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return &object.String{Value: line}
+}
+
+// builtinInput reads a line from stdin after writing the optional prompt.
+func builtinInput(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=0 or 1", len(args))
+	}
+
+	if len(args) == 1 {
+		prompt, err := stringArgs("input", 1, args)
+		if err != nil {
+			return err
+		}
+		io.WriteString(ctx.Stdout, prompt[0])
+	}
+
+	return readLine(ctx)
+}
+
+func builtinReadline(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newArgumentError("wrong number of arguments, got=%d, want=0", len(args))
+	}
+
+	return readLine(ctx)
+}
@@ -0,0 +1,247 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	builtins["json_encode"] = &object.Builtin{Fn: builtinJSONEncode}
+	builtins["json_decode"] = &object.Builtin{Fn: builtinJSONDecode}
+}
+
+// builtinJSONEncode serializes a value to JSON. The optional second argument
+// pretty-prints the output, indenting by that many spaces or by the given
+// string.
+func builtinJSONEncode(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1 or 2", len(args))
+	}
+
+	indent := ""
+	if len(args) == 2 {
+		switch arg := args[1].(type) {
+		case *object.Integer:
+			if arg.Value < 0 {
+				return newValueError("json_encode: indent must be non-negative")
+			}
+
+			if err := checkRepeat("json_encode", " ", arg.Value); err != nil {
+				return err
+			}
+
+			indent = strings.Repeat(" ", int(arg.Value))
+		case *object.String:
+			indent = arg.Value
+		default:
+			return newArgumentError("argument to `json_encode` not supported, want INTEGER or STRING, got %s", arg.Type())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := encodeJSON(&out, args[0], map[object.Object]bool{}); err != nil {
+		return err
+	}
+
+	if indent == "" {
+		return &object.String{Value: out.String()}
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, out.Bytes(), "", indent); err != nil {
+		return newValueError("json_encode: %s", err)
+	}
+
+	return &object.String{Value: pretty.String()}
+}
+
+// encodeJSON writes obj to out. encoding holds the arrays and hashes being
+// encoded, so a value that contains itself is an error rather than endless
+// output.
+func encodeJSON(out *bytes.Buffer, obj object.Object, encoding map[object.Object]bool) *object.Error {
+	switch obj.(type) {
+	case *object.Array, *object.Hash:
+		if encoding[obj] {
+			return newValueError("json_encode: cyclic value")
+		}
+		encoding[obj] = true
+		defer delete(encoding, obj)
+	}
+
+	switch obj := obj.(type) {
+	case *object.Null:
+		out.WriteString("null")
+
+	case *object.Boolean:
+		out.WriteString(strconv.FormatBool(obj.Value))
+
+	case *object.Integer:
+		out.WriteString(strconv.FormatInt(obj.Value, 10))
+
+	case *object.Float:
+		if math.IsInf(obj.Value, 0) || math.IsNaN(obj.Value) {
+			return newValueError("json_encode: unsupported value %s", obj.Inspect())
+		}
+		out.WriteString(strconv.FormatFloat(obj.Value, 'g', -1, 64))
+
+	case *object.String:
+		encodeJSONString(out, obj.Value)
+
+	case *object.Array:
+		return encodeJSONArray(out, obj.Elements, encoding)
+
+	case *object.Tuple:
+		return encodeJSONArray(out, obj.Elements, encoding)
+
+	case *object.Hash:
+		out.WriteByte('{')
+		for i, pair := range obj.Ordered() {
+			key, ok := pair.Key.(*object.String)
+			if !ok {
+				return newValueError("json_encode: hash key must be STRING, got %s", pair.Key.Type())
+			}
+
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			encodeJSONString(out, key.Value)
+			out.WriteByte(':')
+			if err := encodeJSON(out, pair.Value, encoding); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+
+	default:
+		return newValueError("json_encode: unsupported value %s", obj.Type())
+	}
+
+	return nil
+}
+
+func encodeJSONArray(out *bytes.Buffer, elements []object.Object, encoding map[object.Object]bool) *object.Error {
+	out.WriteByte('[')
+	for i, e := range elements {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if err := encodeJSON(out, e, encoding); err != nil {
+			return err
+		}
+	}
+	out.WriteByte(']')
+
+	return nil
+}
+
+func encodeJSONString(out *bytes.Buffer, s string) {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	// Encode terminates every value with a newline
+	out.Truncate(out.Len() - 1)
+}
+
+// builtinJSONDecode parses JSON into Monkey values. Objects become hashes
+// that keep the document's key order, and numbers become integers unless
+// they have a fraction or exponent.
+func builtinJSONDecode(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	s, ok := args[0].(*object.String)
+	if !ok {
+		return newArgumentError("argument to `json_decode` not supported, want STRING, got %s", args[0].Type())
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s.Value))
+	dec.UseNumber()
+
+	value, err := decodeJSON(dec)
+	if err != nil {
+		return newValueError("json_decode: %s", err)
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return newValueError("json_decode: unexpected data after top-level value")
+	}
+
+	return value
+}
+
+func decodeJSON(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case nil:
+		return NULL, nil
+
+	case bool:
+		return nativeBoolToBooleanObject(tok), nil
+
+	case string:
+		return &object.String{Value: tok}, nil
+
+	case json.Number:
+		if i, err := tok.Int64(); err == nil {
+			return &object.Integer{Value: i}, nil
+		}
+
+		f, err := tok.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &object.Float{Value: f}, nil
+
+	case json.Delim:
+		if tok == '[' {
+			elements := []object.Object{}
+			for dec.More() {
+				e, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, e)
+			}
+
+			// consume the closing ]
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return &object.Array{Elements: elements}, nil
+		}
+
+		hash := object.NewHash()
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			key := &object.String{Value: keyTok.(string)}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			hash.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+		}
+
+		// consume the closing }
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return hash, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,324 @@
+package evaluator
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	modules["math"] = &object.Module{
+		Name: "math",
+		Members: map[string]object.Object{
+			"PI":     &object.Float{Value: math.Pi},
+			"E":      &object.Float{Value: math.E},
+			"INF":    &object.Float{Value: math.Inf(1)},
+			"abs":    &object.Builtin{Fn: mathAbs},
+			"min":    &object.Builtin{Fn: mathMin},
+			"max":    &object.Builtin{Fn: mathMax},
+			"pow":    &object.Builtin{Fn: mathPow},
+			"sqrt":   floatFunc("sqrt", math.Sqrt),
+			"floor":  roundingFunc("floor", math.Floor),
+			"ceil":   roundingFunc("ceil", math.Ceil),
+			"round":  roundingFunc("round", math.Round),
+			"clamp":  &object.Builtin{Fn: mathClamp},
+			"sin":    floatFunc("sin", math.Sin),
+			"cos":    floatFunc("cos", math.Cos),
+			"tan":    floatFunc("tan", math.Tan),
+			"asin":   floatFunc("asin", math.Asin),
+			"acos":   floatFunc("acos", math.Acos),
+			"atan":   floatFunc("atan", math.Atan),
+			"atan2":  &object.Builtin{Fn: mathAtan2},
+			"exp":    floatFunc("exp", math.Exp),
+			"log":    floatFunc("log", math.Log),
+			"log10":  floatFunc("log10", math.Log10),
+			"random": &object.Builtin{Fn: mathRandom},
+			"seed":   &object.Builtin{Fn: mathSeed},
+		},
+	}
+}
+
+func numberArg(name string, arg object.Object) (float64, *object.Error) {
+	if !isNumber(arg) {
+		return 0, newArgumentError("argument to `%s` not supported, want INTEGER or FLOAT, got %s", name, arg.Type())
+	}
+
+	return toFloat(arg), nil
+}
+
+// floatFunc wraps a float64 function of one argument as a builtin.
+func floatFunc(name string, fn func(float64) float64) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+			}
+
+			x, err := numberArg(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			return &object.Float{Value: fn(x)}
+		},
+	}
+}
+
+// roundingFunc wraps floor, ceil and round, which turn floats into integers
+// and leave integers as they are. A float with no integer value, such as
+// NaN, an infinity or one beyond the int64 range, is an error.
+func roundingFunc(name string, fn func(float64) float64) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+			}
+
+			x, err := numberArg(name, args[0])
+			if err != nil {
+				return err
+			}
+
+			if i, ok := args[0].(*object.Integer); ok {
+				return i
+			}
+
+			r := fn(x)
+			if math.IsNaN(r) || r < math.MinInt64 || r >= math.MaxInt64 {
+				return newArgumentError("argument to `%s` not supported, %s is out of INTEGER range", name, args[0].Inspect())
+			}
+
+			return &object.Integer{Value: int64(r)}
+		},
+	}
+}
+
+func mathAbs(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		if arg.Value < 0 {
+			return &object.Integer{Value: -arg.Value}
+		}
+		return arg
+	case *object.Float:
+		return &object.Float{Value: math.Abs(arg.Value)}
+	default:
+		return newArgumentError("argument to `abs` not supported, want INTEGER or FLOAT, got %s", args[0].Type())
+	}
+}
+
+// extremum implements min and max over either the arguments or a single
+// array argument, returning the winning object unchanged.
+func extremum(name string, args []object.Object, better func(a, b float64) bool) object.Object {
+	if len(args) == 1 {
+		if arr, ok := args[0].(*object.Array); ok {
+			args = arr.Elements
+		}
+	}
+
+	if len(args) == 0 {
+		return newArgumentError("argument to `%s` not supported, no values", name)
+	}
+
+	best := args[0]
+	for _, arg := range args {
+		x, err := numberArg(name, arg)
+		if err != nil {
+			return err
+		}
+
+		if better(x, toFloat(best)) {
+			best = arg
+		}
+	}
+
+	return best
+}
+
+func mathMin(ctx *object.Context, args ...object.Object) object.Object {
+	return extremum("min", args, func(a, b float64) bool { return a < b })
+}
+
+func mathMax(ctx *object.Context, args ...object.Object) object.Object {
+	return extremum("max", args, func(a, b float64) bool { return a > b })
+}
+
+// mathPow stays in integers when raising an integer to a non-negative
+// integer power, unless the result overflows an int64, in which case it
+// gives the float result instead.
+func mathPow(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	base, err := numberArg("pow", args[0])
+	if err != nil {
+		return err
+	}
+
+	exp, err := numberArg("pow", args[1])
+	if err != nil {
+		return err
+	}
+
+	b, bOk := args[0].(*object.Integer)
+	e, eOk := args[1].(*object.Integer)
+	if bOk && eOk && e.Value >= 0 {
+		if result, ok := intPow(b.Value, e.Value); ok {
+			return &object.Integer{Value: result}
+		}
+	}
+
+	return &object.Float{Value: math.Pow(base, exp)}
+}
+
+// intPow raises base to exp by squaring, reporting false on overflow.
+func intPow(base, exp int64) (int64, bool) {
+	result := int64(1)
+	for exp > 0 {
+		var ok bool
+		if exp&1 == 1 {
+			if result, ok = mulInt(result, base); !ok {
+				return 0, false
+			}
+		}
+
+		exp >>= 1
+		if exp > 0 {
+			if base, ok = mulInt(base, base); !ok {
+				return 0, false
+			}
+		}
+	}
+
+	return result, true
+}
+
+// mulInt multiplies a and b, reporting false on overflow.
+func mulInt(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	c := a * b
+	if c/b != a || a == math.MinInt64 && b == -1 {
+		return 0, false
+	}
+
+	return c, true
+}
+
+func mathClamp(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newArgumentError("wrong number of arguments, got=%d, want=3", len(args))
+	}
+
+	for _, arg := range args {
+		if _, err := numberArg("clamp", arg); err != nil {
+			return err
+		}
+	}
+
+	x, lo, hi := args[0], args[1], args[2]
+	if toFloat(lo) > toFloat(hi) {
+		return newArgumentError("argument to `clamp` not supported, lower bound %s above upper bound %s", lo.Inspect(), hi.Inspect())
+	}
+
+	if toFloat(x) < toFloat(lo) {
+		return lo
+	}
+
+	if toFloat(x) > toFloat(hi) {
+		return hi
+	}
+
+	return x
+}
+
+func mathAtan2(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	y, err := numberArg("atan2", args[0])
+	if err != nil {
+		return err
+	}
+
+	x, err := numberArg("atan2", args[1])
+	if err != nil {
+		return err
+	}
+
+	return &object.Float{Value: math.Atan2(y, x)}
+}
+
+// mathRandom returns a float in [0, 1) with no arguments, an integer in
+// [0, n) for random(n) and an integer in [lo, hi) for random(lo, hi).
+func mathRandom(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) > 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=0 to 2", len(args))
+	}
+
+	random := ctx.Random()
+	if len(args) == 0 {
+		return &object.Float{Value: random.Float64()}
+	}
+
+	lo, hi := int64(0), int64(0)
+	for i, arg := range args {
+		n, err := integerArg("random", arg)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 || i == 1 {
+			hi = n
+		} else {
+			lo = n
+		}
+	}
+
+	if hi <= lo {
+		return newArgumentError("argument to `random` not supported, empty interval [%d, %d)", lo, hi)
+	}
+
+	// the span of an interval such as [MinInt64, MaxInt64) does not fit
+	// in an int64, so it is drawn from the full 64 bits instead
+	span := uint64(hi) - uint64(lo)
+	if span <= math.MaxInt64 {
+		return &object.Integer{Value: lo + random.Int63n(int64(span))}
+	}
+
+	return &object.Integer{Value: int64(uint64(lo) + randomBelow(random, span))}
+}
+
+// randomBelow draws uniformly from [0, n), rejecting draws from the
+// incomplete run of n at the top of the uint64 range.
+func randomBelow(random *rand.Rand, n uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := random.Uint64(); v < limit {
+			return v % n
+		}
+	}
+}
+
+func mathSeed(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	seed, err := integerArg("seed", args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx.SeedRandom(seed)
+	return NULL
+}
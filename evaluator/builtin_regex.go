@@ -0,0 +1,252 @@
+package evaluator
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+// Patterns given as strings are compiled once and reused. The cache holds
+// at most regexCacheSize patterns so scripts that build patterns on the fly
+// cannot grow it without limit.
+const regexCacheSize = 256
+
+var (
+	regexMu    sync.Mutex
+	regexCache = map[string]*regexp.Regexp{}
+)
+
+func init() {
+	modules["regex"] = &object.Module{
+		Name: "regex",
+		Members: map[string]object.Object{
+			"compile":  &object.Builtin{Fn: regexCompile},
+			"test":     &object.Builtin{Fn: regexTest},
+			"match":    &object.Builtin{Fn: regexMatch},
+			"groups":   &object.Builtin{Fn: regexGroups},
+			"find_all": &object.Builtin{Fn: regexFindAll},
+			"replace":  &object.Builtin{Fn: regexReplace},
+			"split":    &object.Builtin{Fn: regexSplit},
+		},
+	}
+}
+
+func compileRegex(pattern string) (*regexp.Regexp, *object.Error) {
+	regexMu.Lock()
+	defer regexMu.Unlock()
+
+	if re, ok := regexCache[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, newValueError("regex: %s", err)
+	}
+
+	if len(regexCache) >= regexCacheSize {
+		for key := range regexCache {
+			delete(regexCache, key)
+			break
+		}
+	}
+	regexCache[pattern] = re
+	return re, nil
+}
+
+// regexArgs validates (pattern, string, ...) where the pattern is either a
+// compiled regex or a string.
+func regexArgs(name string, args []object.Object, min, max int) (*regexp.Regexp, string, *object.Error) {
+	if len(args) < min || len(args) > max {
+		if min == max {
+			return nil, "", newArgumentError("wrong number of arguments, got=%d, want=%d", len(args), min)
+		}
+		return nil, "", newArgumentError("wrong number of arguments, got=%d, want=%d or %d", len(args), min, max)
+	}
+
+	var re *regexp.Regexp
+	switch pattern := args[0].(type) {
+	case *object.Regex:
+		re = pattern.Value
+	case *object.String:
+		compiled, err := compileRegex(pattern.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		re = compiled
+	default:
+		return nil, "", newArgumentError("argument to `%s` not supported, want REGEX or STRING, got %s", name, args[0].Type())
+	}
+
+	s, ok := args[1].(*object.String)
+	if !ok {
+		return nil, "", newArgumentError("argument to `%s` not supported, want STRING, got %s", name, args[1].Type())
+	}
+
+	return re, s.Value, nil
+}
+
+// limitArg reads the optional match limit, where -1 means no limit.
+func limitArg(name string, args []object.Object, i int) (int, *object.Error) {
+	if len(args) <= i {
+		return -1, nil
+	}
+
+	n, err := integerArg(name, args[i])
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// submatch returns the text of group i, or NULL when it did not take part
+// in the match.
+func submatch(s string, loc []int, i int) object.Object {
+	if loc[2*i] < 0 {
+		return NULL
+	}
+
+	return &object.String{Value: s[loc[2*i]:loc[2*i+1]]}
+}
+
+func regexCompile(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("compile", 1, args)
+	if err != nil {
+		return err
+	}
+
+	re, err := compileRegex(values[0])
+	if err != nil {
+		return err
+	}
+
+	return &object.Regex{Value: re}
+}
+
+func regexTest(ctx *object.Context, args ...object.Object) object.Object {
+	re, s, err := regexArgs("test", args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(re.MatchString(s))
+}
+
+// regexMatch returns the first match followed by its capture groups, or
+// null when the pattern does not match.
+func regexMatch(ctx *object.Context, args ...object.Object) object.Object {
+	re, s, err := regexArgs("match", args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return NULL
+	}
+
+	groups := make([]object.Object, len(loc)/2)
+	for i := range groups {
+		groups[i] = submatch(s, loc, i)
+	}
+
+	return &object.Array{Elements: groups}
+}
+
+// regexGroups returns the named capture groups of the first match as a
+// hash, or null when the pattern does not match.
+func regexGroups(ctx *object.Context, args ...object.Object) object.Object {
+	re, s, err := regexArgs("groups", args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return NULL
+	}
+
+	hash := object.NewHash()
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		key := &object.String{Value: name}
+		hash.Set(key.HashKey(), object.HashPair{Key: key, Value: submatch(s, loc, i)})
+	}
+
+	return hash
+}
+
+func regexFindAll(ctx *object.Context, args ...object.Object) object.Object {
+	re, s, err := regexArgs("find_all", args, 2, 3)
+	if err != nil {
+		return err
+	}
+
+	n, err := limitArg("find_all", args, 2)
+	if err != nil {
+		return err
+	}
+
+	return stringsToArray(re.FindAllString(s, n))
+}
+
+// regexReplace replaces every match with a template that may refer to
+// groups as $1 or ${name}, or with the result of calling a function on the
+// matched text.
+func regexReplace(ctx *object.Context, args ...object.Object) object.Object {
+	re, s, err := regexArgs("replace", args, 3, 3)
+	if err != nil {
+		return err
+	}
+
+	switch repl := args[2].(type) {
+	case *object.String:
+		return &object.String{Value: re.ReplaceAllString(s, repl.Value)}
+
+	case *object.Function, *object.Builtin:
+		var replErr object.Object
+		result := re.ReplaceAllStringFunc(s, func(match string) string {
+			if replErr != nil {
+				return match
+			}
+
+			v := applyFunction(ctx, repl, []object.Object{&object.String{Value: match}})
+			str, ok := v.(*object.String)
+			if !ok {
+				replErr = v
+				if !isError(v) {
+					replErr = newError("replacement for `replace` must return STRING, got %s", v.Type())
+				}
+				return match
+			}
+			return str.Value
+		})
+		if replErr != nil {
+			return replErr
+		}
+
+		return &object.String{Value: result}
+
+	default:
+		return newArgumentError("argument to `replace` not supported, want STRING or FUNCTION, got %s", args[2].Type())
+	}
+}
+
+func regexSplit(ctx *object.Context, args ...object.Object) object.Object {
+	re, s, err := regexArgs("split", args, 2, 3)
+	if err != nil {
+		return err
+	}
+
+	n, err := limitArg("split", args, 2)
+	if err != nil {
+		return err
+	}
+
+	return stringsToArray(re.Split(s, n))
+}
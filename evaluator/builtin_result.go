@@ -0,0 +1,140 @@
+package evaluator
+
+import (
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	builtins["ok"] = &object.Builtin{Fn: builtinOk}
+	builtins["err"] = &object.Builtin{Fn: builtinErr}
+	builtins["is_ok"] = &object.Builtin{Fn: builtinIsOk}
+	builtins["is_err"] = &object.Builtin{Fn: builtinIsErr}
+	builtins["unwrap"] = &object.Builtin{Fn: builtinUnwrap}
+	builtins["unwrap_err"] = &object.Builtin{Fn: builtinUnwrapErr}
+	builtins["unwrap_or"] = &object.Builtin{Fn: builtinUnwrapOr}
+}
+
+func resultArg(name string, arg object.Object) (*object.Result, *object.Error) {
+	result, ok := arg.(*object.Result)
+	if !ok {
+		return nil, newArgumentError("argument to `%s` not supported, want RESULT, got %s", name, arg.Type())
+	}
+
+	return result, nil
+}
+
+func builtinOk(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	return &object.Result{Ok: true, Value: args[0]}
+}
+
+func builtinErr(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	return &object.Result{Ok: false, Value: args[0]}
+}
+
+func builtinIsOk(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	result, err := resultArg("is_ok", args[0])
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(result.Ok)
+}
+
+func builtinIsErr(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	result, err := resultArg("is_err", args[0])
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(!result.Ok)
+}
+
+// builtinUnwrap returns the value of an ok result, and raises an error for
+// an err one.
+func builtinUnwrap(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	result, err := resultArg("unwrap", args[0])
+	if err != nil {
+		return err
+	}
+
+	if !result.Ok {
+		return newError("unwrap: called on %s", result.Inspect())
+	}
+
+	return result.Value
+}
+
+func builtinUnwrapErr(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	result, err := resultArg("unwrap_err", args[0])
+	if err != nil {
+		return err
+	}
+
+	if result.Ok {
+		return newError("unwrap_err: called on %s", result.Inspect())
+	}
+
+	return result.Value
+}
+
+func builtinUnwrapOr(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	result, err := resultArg("unwrap_or", args[0])
+	if err != nil {
+		return err
+	}
+
+	if !result.Ok {
+		return args[1]
+	}
+
+	return result.Value
+}
+
+// evalPropagateExpression unwraps an ok result, and returns an err result
+// from the enclosing function the same way a return statement would.
+func evalPropagateExpression(node *ast.PropagateExpression, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isAbrupt(val) {
+		return val
+	}
+
+	result, ok := val.(*object.Result)
+	if !ok {
+		return newTypeError("unknown operator: %s?", val.Type())
+	}
+
+	if !result.Ok {
+		return &object.ReturnValue{Value: result}
+	}
+
+	return result.Value
+}
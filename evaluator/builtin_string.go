@@ -0,0 +1,410 @@
+package evaluator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	builtins["split"] = &object.Builtin{Fn: builtinSplit}
+	builtins["join"] = &object.Builtin{Fn: builtinJoin}
+	builtins["trim"] = &object.Builtin{Fn: builtinTrim}
+	builtins["upper"] = &object.Builtin{Fn: builtinUpper}
+	builtins["lower"] = &object.Builtin{Fn: builtinLower}
+	builtins["contains"] = &object.Builtin{Fn: builtinContains}
+	builtins["starts_with"] = &object.Builtin{Fn: builtinStartsWith}
+	builtins["ends_with"] = &object.Builtin{Fn: builtinEndsWith}
+	builtins["replace"] = &object.Builtin{Fn: builtinReplace}
+	builtins["index_of"] = &object.Builtin{Fn: builtinIndexOf}
+	builtins["repeat"] = &object.Builtin{Fn: builtinRepeat}
+	builtins["pad_left"] = &object.Builtin{Fn: builtinPadLeft}
+	builtins["pad_right"] = &object.Builtin{Fn: builtinPadRight}
+	builtins["chars"] = &object.Builtin{Fn: builtinChars}
+	builtins["format"] = &object.Builtin{Fn: builtinFormat}
+}
+
+// stringArgs checks that args holds exactly want strings and returns their
+// values.
+func stringArgs(name string, want int, args []object.Object) ([]string, *object.Error) {
+	if len(args) != want {
+		return nil, newArgumentError("wrong number of arguments, got=%d, want=%d", len(args), want)
+	}
+
+	values := make([]string, len(args))
+	for i, arg := range args {
+		s, ok := arg.(*object.String)
+		if !ok {
+			return nil, newArgumentError("argument to `%s` not supported, want STRING, got %s", name, arg.Type())
+		}
+		values[i] = s.Value
+	}
+
+	return values, nil
+}
+
+func integerArg(name string, arg object.Object) (int64, *object.Error) {
+	i, ok := arg.(*object.Integer)
+	if !ok {
+		return 0, newArgumentError("argument to `%s` not supported, want INTEGER, got %s", name, arg.Type())
+	}
+
+	return i.Value, nil
+}
+
+func stringsToArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, v := range values {
+		elements[i] = &object.String{Value: v}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinSplit(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("split", 2, args)
+	if err != nil {
+		return err
+	}
+
+	return stringsToArray(strings.Split(values[0], values[1]))
+}
+
+func builtinJoin(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newArgumentError("argument to `join` not supported, want ARRAY, got %s", args[0].Type())
+	}
+
+	sep, ok := args[1].(*object.String)
+	if !ok {
+		return newArgumentError("argument to `join` not supported, want STRING, got %s", args[1].Type())
+	}
+
+	parts := make([]string, len(arr.Elements))
+	for i, e := range arr.Elements {
+		s, ok := e.(*object.String)
+		if !ok {
+			return newArgumentError("argument to `join` not supported, want ARRAY of STRING, got %s element", e.Type())
+		}
+		parts[i] = s.Value
+	}
+
+	return &object.String{Value: strings.Join(parts, sep.Value)}
+}
+
+// builtinTrim strips surrounding whitespace, or the characters of the
+// optional second argument.
+func builtinTrim(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) == 2 {
+		values, err := stringArgs("trim", 2, args)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: strings.Trim(values[0], values[1])}
+	}
+
+	values, err := stringArgs("trim", 1, args)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.TrimSpace(values[0])}
+}
+
+func builtinUpper(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("upper", 1, args)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.ToUpper(values[0])}
+}
+
+func builtinLower(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("lower", 1, args)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.ToLower(values[0])}
+}
+
+func builtinContains(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("contains", 2, args)
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(strings.Contains(values[0], values[1]))
+}
+
+func builtinStartsWith(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("starts_with", 2, args)
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(strings.HasPrefix(values[0], values[1]))
+}
+
+func builtinEndsWith(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("ends_with", 2, args)
+	if err != nil {
+		return err
+	}
+
+	return nativeBoolToBooleanObject(strings.HasSuffix(values[0], values[1]))
+}
+
+// builtinReplace replaces every occurrence, or only the first n when a
+// count is given.
+func builtinReplace(ctx *object.Context, args ...object.Object) object.Object {
+	n := int64(-1)
+	if len(args) == 4 {
+		count, err := integerArg("replace", args[3])
+		if err != nil {
+			return err
+		}
+		n, args = count, args[:3]
+	}
+
+	values, err := stringArgs("replace", 3, args)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.Replace(values[0], values[1], values[2], int(n))}
+}
+
+// builtinIndexOf returns the character position of the first occurrence of
+// the substring, or -1.
+func builtinIndexOf(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("index_of", 2, args)
+	if err != nil {
+		return err
+	}
+
+	i := strings.Index(values[0], values[1])
+	if i < 0 {
+		return &object.Integer{Value: -1}
+	}
+
+	return &object.Integer{Value: int64(utf8.RuneCountInString(values[0][:i]))}
+}
+
+// MaxStringLength is the longest string, in bytes, that builtins such as
+// repeat build from a count given by the script.
+const MaxStringLength = 1 << 30
+
+// checkRepeat reports an error when n copies of s would be longer than
+// MaxStringLength.
+func checkRepeat(name, s string, n int64) *object.Error {
+	if len(s) > 0 && n > int64(MaxStringLength/len(s)) {
+		return newArgumentError("argument to `%s` not supported, result longer than %d bytes", name, MaxStringLength)
+	}
+
+	return nil
+}
+
+func builtinRepeat(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=2", len(args))
+	}
+
+	values, err := stringArgs("repeat", 1, args[:1])
+	if err != nil {
+		return err
+	}
+
+	n, err := integerArg("repeat", args[1])
+	if err != nil {
+		return err
+	}
+
+	if n < 0 {
+		return newArgumentError("argument to `repeat` not supported, negative count %d", n)
+	}
+
+	if err := checkRepeat("repeat", values[0], n); err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.Repeat(values[0], int(n))}
+}
+
+// padArgs validates (string, width, pad?) where pad defaults to a space.
+func padArgs(name string, args []object.Object) (string, int, string, *object.Error) {
+	if len(args) != 2 && len(args) != 3 {
+		return "", 0, "", newArgumentError("wrong number of arguments, got=%d, want=2 or 3", len(args))
+	}
+
+	values, err := stringArgs(name, 1, args[:1])
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	width, err := integerArg(name, args[1])
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	pad := " "
+	if len(args) == 3 {
+		p, err := stringArgs(name, 1, args[2:])
+		if err != nil {
+			return "", 0, "", err
+		}
+		if p[0] == "" {
+			return "", 0, "", newArgumentError("argument to `%s` not supported, empty padding", name)
+		}
+		pad = p[0]
+	}
+
+	missing := width - int64(utf8.RuneCountInString(values[0]))
+	if missing < 0 {
+		missing = 0
+	}
+
+	if err := checkRepeat(name, pad, paddingCopies(pad, missing)); err != nil {
+		return "", 0, "", err
+	}
+
+	return values[0], int(missing), pad, nil
+}
+
+// paddingCopies is how many copies of pad cover n runes.
+func paddingCopies(pad string, n int64) int64 {
+	return n/int64(utf8.RuneCountInString(pad)) + 1
+}
+
+func padding(pad string, n int) string {
+	runes := []rune(strings.Repeat(pad, int(paddingCopies(pad, int64(n)))))
+	return string(runes[:n])
+}
+
+func builtinPadLeft(ctx *object.Context, args ...object.Object) object.Object {
+	s, missing, pad, err := padArgs("pad_left", args)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: padding(pad, missing) + s}
+}
+
+func builtinPadRight(ctx *object.Context, args ...object.Object) object.Object {
+	s, missing, pad, err := padArgs("pad_right", args)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: s + padding(pad, missing)}
+}
+
+func builtinChars(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("chars", 1, args)
+	if err != nil {
+		return err
+	}
+
+	chars := []string{}
+	for _, r := range values[0] {
+		chars = append(chars, string(r))
+	}
+
+	return stringsToArray(chars)
+}
+
+// builtinFormat implements printf-style formatting. Each verb (with any
+// flags, width and precision) is checked against the object type it is
+// given and then handed to fmt with the underlying Go value.
+func builtinFormat(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1+", len(args))
+	}
+
+	format, ok := args[0].(*object.String)
+	if !ok {
+		return newArgumentError("argument to `format` not supported, want STRING, got %s", args[0].Type())
+	}
+
+	var out bytes.Buffer
+	rest := args[1:]
+	f := format.Value
+	for i := 0; i < len(f); i++ {
+		if f[i] != '%' {
+			out.WriteByte(f[i])
+			continue
+		}
+
+		start := i
+		i++
+		for i < len(f) && strings.IndexByte("+-# 0123456789.", f[i]) >= 0 {
+			i++
+		}
+		if i >= len(f) {
+			return newValueError("format: incomplete verb %q", f[start:])
+		}
+
+		spec, verb := f[start:i+1], f[i]
+		if verb == '%' {
+			out.WriteByte('%')
+			continue
+		}
+
+		if len(rest) == 0 {
+			return newValueError("format: missing argument for %s", spec)
+		}
+		arg := rest[0]
+		rest = rest[1:]
+
+		value, err := formatValue(spec, verb, arg)
+		if err != nil {
+			return err
+		}
+		out.WriteString(value)
+	}
+
+	if len(rest) > 0 {
+		return newValueError("format: %d unused arguments", len(rest))
+	}
+
+	return &object.String{Value: out.String()}
+}
+
+func formatValue(spec string, verb byte, arg object.Object) (string, *object.Error) {
+	switch verb {
+	case 'd', 'x', 'X', 'o', 'b', 'c':
+		if i, ok := arg.(*object.Integer); ok {
+			return fmt.Sprintf(spec, i.Value), nil
+		}
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		if isNumber(arg) {
+			return fmt.Sprintf(spec, toFloat(arg)), nil
+		}
+	case 't':
+		if b, ok := arg.(*object.Boolean); ok {
+			return fmt.Sprintf(spec, b.Value), nil
+		}
+	case 's', 'q':
+		if s, ok := arg.(*object.String); ok {
+			return fmt.Sprintf(spec, s.Value), nil
+		}
+		if verb == 's' {
+			return fmt.Sprintf(spec, arg.Inspect()), nil
+		}
+	case 'v':
+		return fmt.Sprintf(spec[:len(spec)-1]+"s", arg.Inspect()), nil
+	default:
+		return "", newValueError("format: unknown verb %s", spec)
+	}
+
+	return "", newValueError("format: %s not supported for %s", spec, arg.Type())
+}
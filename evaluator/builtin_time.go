@@ -0,0 +1,200 @@
+package evaluator
+
+import (
+	"math"
+	"time"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	modules["time"] = &object.Module{
+		Name: "time",
+		Members: map[string]object.Object{
+			"MILLISECOND": &object.Duration{Value: time.Millisecond},
+			"SECOND":      &object.Duration{Value: time.Second},
+			"MINUTE":      &object.Duration{Value: time.Minute},
+			"HOUR":        &object.Duration{Value: time.Hour},
+			"now":         &object.Builtin{Fn: timeNow},
+			"since":       &object.Builtin{Fn: timeSince},
+			"date":        &object.Builtin{Fn: timeDate},
+			"parse":       &object.Builtin{Fn: timeParse},
+			"format":      &object.Builtin{Fn: timeFormat},
+			"unix":        &object.Builtin{Fn: timeUnix},
+			"from_unix":   &object.Builtin{Fn: timeFromUnix},
+			"duration":    &object.Builtin{Fn: timeDuration},
+			"seconds":     &object.Builtin{Fn: timeSeconds},
+		},
+	}
+}
+
+func timeArg(name string, arg object.Object) (time.Time, *object.Error) {
+	t, ok := arg.(*object.Time)
+	if !ok {
+		return time.Time{}, newArgumentError("argument to `%s` not supported, want TIME, got %s", name, arg.Type())
+	}
+
+	return t.Value, nil
+}
+
+// layoutArg reads an optional Go reference layout such as "2006-01-02".
+func layoutArg(name string, args []object.Object, i int, layout string) (string, *object.Error) {
+	if len(args) <= i {
+		return layout, nil
+	}
+
+	values, err := stringArgs(name, 1, args[i:i+1])
+	if err != nil {
+		return "", err
+	}
+
+	return values[0], nil
+}
+
+func timeNow(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newArgumentError("wrong number of arguments, got=%d, want=0", len(args))
+	}
+
+	return &object.Time{Value: ctx.Now()}
+}
+
+func timeSince(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	t, err := timeArg("since", args[0])
+	if err != nil {
+		return err
+	}
+
+	return &object.Duration{Value: ctx.Now().Sub(t)}
+}
+
+// timeDate builds a UTC time from year, month and day, optionally followed
+// by hour, minute and second.
+func timeDate(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) < 3 || len(args) > 6 {
+		return newArgumentError("wrong number of arguments, got=%d, want=3 to 6", len(args))
+	}
+
+	parts := make([]int, 6)
+	for i, arg := range args {
+		n, err := integerArg("date", arg)
+		if err != nil {
+			return err
+		}
+		parts[i] = int(n)
+	}
+
+	t := time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, time.UTC)
+	return &object.Time{Value: t}
+}
+
+// timeParse reads RFC 3339 timestamps, or any format given as a Go
+// reference layout.
+func timeParse(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1 or 2", len(args))
+	}
+
+	values, err := stringArgs("parse", 1, args[:1])
+	if err != nil {
+		return err
+	}
+
+	layout, err := layoutArg("parse", args, 1, time.RFC3339)
+	if err != nil {
+		return err
+	}
+
+	t, parseErr := time.Parse(layout, values[0])
+	if parseErr != nil {
+		return newValueError("time: %s", parseErr)
+	}
+
+	return &object.Time{Value: t}
+}
+
+func timeFormat(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1 or 2", len(args))
+	}
+
+	t, err := timeArg("format", args[0])
+	if err != nil {
+		return err
+	}
+
+	layout, err := layoutArg("format", args, 1, time.RFC3339Nano)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: t.Format(layout)}
+}
+
+func timeUnix(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	t, err := timeArg("unix", args[0])
+	if err != nil {
+		return err
+	}
+
+	return &object.Integer{Value: t.Unix()}
+}
+
+func timeFromUnix(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	if n, ok := args[0].(*object.Integer); ok {
+		return &object.Time{Value: time.Unix(n.Value, 0).UTC()}
+	}
+
+	secs, err := numberArg("from_unix", args[0])
+	if err != nil {
+		return err
+	}
+
+	if math.IsNaN(secs) || secs < math.MinInt64 || secs >= math.MaxInt64 {
+		return newArgumentError("argument to `from_unix` not supported, %s is out of range", args[0].Inspect())
+	}
+
+	whole, frac := math.Modf(secs)
+	return &object.Time{Value: time.Unix(int64(whole), int64(frac*float64(time.Second))).UTC()}
+}
+
+// timeDuration parses durations such as "1h30m" or "250ms".
+func timeDuration(ctx *object.Context, args ...object.Object) object.Object {
+	values, err := stringArgs("duration", 1, args)
+	if err != nil {
+		return err
+	}
+
+	d, parseErr := time.ParseDuration(values[0])
+	if parseErr != nil {
+		// the message already reads "time: invalid duration ..."
+		return newValueError("%s", parseErr)
+	}
+
+	return &object.Duration{Value: d}
+}
+
+func timeSeconds(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1", len(args))
+	}
+
+	d, ok := args[0].(*object.Duration)
+	if !ok {
+		return newArgumentError("argument to `seconds` not supported, want DURATION, got %s", args[0].Type())
+	}
+
+	return &object.Float{Value: d.Value.Seconds()}
+}
@@ -0,0 +1,81 @@
+package evaluator
+
+import (
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func evalClassStatement(node *ast.ClassStatement, env *object.Environment) object.Object {
+	class := &object.Class{
+		Name:    node.Name.Value,
+		Methods: make(map[string]*object.Function),
+	}
+
+	if node.Superclass != nil {
+		super := evalIdentifier(node.Superclass, env)
+		if isError(super) {
+			return super
+		}
+
+		superclass, ok := super.(*object.Class)
+		if !ok {
+			return newError("superclass must be a class, got %s", super.Type())
+		}
+		class.Superclass = superclass
+	}
+
+	for _, m := range node.Methods {
+		class.Methods[m.Name.Value] = &object.Function{
+			Parameters: m.Function.Parameters,
+			Body:       m.Function.Body,
+			Env:        env,
+		}
+	}
+
+	env.Set(class.Name, class)
+	return NULL
+}
+
+// bindMethod returns fn with self bound to the object and, when owner has a
+// superclass, super bound to continue the method lookup from there.
+func bindMethod(self *object.ClassObject, fn *object.Function, owner *object.Class) *object.Function {
+	env := object.NewEnclosedEnvironment(fn.Env)
+	env.Set("self", self)
+	if owner.Superclass != nil {
+		env.Set("super", &object.Super{Self: self, Class: owner.Superclass})
+	}
+
+	return &object.Function{Parameters: fn.Parameters, Body: fn.Body, Env: env}
+}
+
+func instantiate(ctx *object.Context, class *object.Class, args []object.Object) object.Object {
+	self := object.NewClassObject(class)
+
+	initFn, owner := class.FindMethod("init")
+	if initFn == nil {
+		if len(args) != 0 {
+			return newArgumentError("wrong number of arguments, got=%d, want=0", len(args))
+		}
+		return self
+	}
+
+	if result := applyFunction(ctx, bindMethod(self, initFn, owner), args); isError(result) {
+		return result
+	}
+
+	return self
+}
+
+// evalObjectMember reads a field of the object, or else one of its class's
+// methods bound to it. The nil result means there is neither.
+func evalObjectMember(self *object.ClassObject, name string) object.Object {
+	if val, ok := self.Get(name); ok {
+		return val
+	}
+
+	if fn, owner := self.Class.FindMethod(name); fn != nil {
+		return bindMethod(self, fn, owner)
+	}
+
+	return nil
+}
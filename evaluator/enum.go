@@ -0,0 +1,40 @@
+package evaluator
+
+import (
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func evalEnumStatement(node *ast.EnumStatement, env *object.Environment) object.Object {
+	enum := &object.Enum{Name: node.Name.Value}
+
+	for _, v := range node.Variants {
+		variant := &object.Variant{Enum: enum, Name: v.Name.Value}
+		for _, f := range v.Fields {
+			variant.Fields = append(variant.Fields, f.Value)
+		}
+
+		if len(variant.Fields) == 0 {
+			variant.Unit = &object.EnumValue{Variant: variant}
+		}
+		enum.Variants = append(enum.Variants, variant)
+	}
+
+	env.Set(enum.Name, enum)
+	return NULL
+}
+
+// evalEnumMember returns the named variant of the enum: the variant's only
+// value when it has no fields, or the variant itself to be called.
+func evalEnumMember(enum *object.Enum, name string) object.Object {
+	variant := enum.Variant(name)
+	if variant == nil {
+		return nil
+	}
+
+	if variant.Unit != nil {
+		return variant.Unit
+	}
+
+	return variant
+}
@@ -2,6 +2,9 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/elsonwu/monkey-go/ast"
 	"github.com/elsonwu/monkey-go/object"
@@ -11,6 +14,9 @@ var (
 	TRUE  = &object.Boolean{Value: true}
 	FALSE = &object.Boolean{Value: false}
 	NULL  = &object.Null{}
+
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
@@ -21,32 +27,85 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 
+	case *ast.WhileExpression:
+		return evalWhileExpression(node, env)
+
+	case *ast.ForExpression:
+		return evalForExpression(node, env)
+
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+
+	case *ast.ClassStatement:
+		return evalClassStatement(node, env)
+
+	case *ast.EnumStatement:
+		return evalEnumStatement(node, env)
+
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+
+	case *ast.PropagateExpression:
+		return evalPropagateExpression(node, env)
+
+	case *ast.MacroLiteral:
+		return newError("macro must be defined with a top-level let")
+
+	case *ast.StructStatement:
+		fields := make([]string, len(node.Fields))
+		for i, f := range node.Fields {
+			fields[i] = f.Value
+		}
+
+		env.Set(node.Name.Value, &object.Struct{Name: node.Name.Value, Fields: fields})
+
+	case *ast.MemberExpression:
+		result, _ := evalChain(node, env)
+		return result
+
+	case *ast.BreakStatement:
+		return BREAK
+
+	case *ast.ContinueStatement:
+		return CONTINUE
+
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 
 	case *ast.ArrayLiteral:
 		return evalArrayLiteral(node, env)
 
+	case *ast.TupleLiteral:
+		elements := evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isAbrupt(elements[0]) {
+			return elements[0]
+		}
+
+		return &object.Tuple{Elements: elements}
+
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
 
 	case *ast.IndexExpression:
+		result, _ := evalChain(node, env)
+		return result
 
-		left := Eval(node.Left, env)
-		if isError(left) {
-			return left
-		}
+	case *ast.SliceExpression:
+		result, _ := evalChain(node, env)
+		return result
 
-		index := Eval(node.Index, env)
-		if isError(index) {
-			return index
-		}
-
-		return evalIndexExpression(left, index)
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
 
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
-		if isError(val) {
+		if isAbrupt(val) {
 			return val
 		}
 
@@ -57,12 +116,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 
 	case *ast.InfixExpression:
 		left := Eval(node.Left, env)
-		if isError(left) {
+		if isAbrupt(left) {
 			return left
 		}
 
 		right := Eval(node.Right, env)
-		if isError(right) {
+		if isAbrupt(right) {
 			return right
 		}
 		return evalInfixExpression(node.Operator, left, right)
@@ -78,21 +137,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalBlockStatement(node, env)
 
 	case *ast.CallExpression:
-		fn := Eval(node.Function, env)
-		if isError(fn) {
-			return fn
-		}
-
-		args := evalExpressions(node.Arguments, env)
-		if len(args) == 1 && isError(args[0]) {
-			return args[0]
-		}
-
-		return applyFunction(fn, args)
+		result, _ := evalChain(node, env)
+		return result
 
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
-		if isError(right) {
+		if isAbrupt(right) {
 			return right
 		}
 		return evalPrefixExpression(node.Operator, right)
@@ -103,16 +153,26 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
+
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
 
 	case *ast.LetStatement:
 		val := Eval(node.Value, env)
 
-		if isError(val) {
+		if isAbrupt(val) {
 			return val
 		}
 
+		if node.Pattern != nil {
+			if err := destructure(node.Pattern, val, env); err != nil {
+				return err
+			}
+			break
+		}
+
 		env.Set(node.Name.Value, val)
 	}
 
@@ -122,18 +182,73 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 func evalIndexExpression(left object.Object, index object.Object) object.Object {
 	switch l := left.(type) {
 	case *object.Array:
+		i, ok := index.(*object.Integer)
+		if !ok {
+			return newTypeError("index operator not supported: %s[%s]", left.Type(), index.Type())
+		}
+
+		idx, ok := normalizeIndex(i.Value, int64(len(l.Elements)))
+		if !ok {
+			return NULL
+		}
+
+		return l.Elements[idx]
+
+	case *object.Tuple:
+		i, ok := index.(*object.Integer)
+		if !ok {
+			return newTypeError("index operator not supported: %s[%s]", left.Type(), index.Type())
+		}
 
-		idx := index.(*object.Integer).Value
-		maxIndex := int64(len(l.Elements) - 1)
-		if idx < 0 || idx > maxIndex {
+		idx, ok := normalizeIndex(i.Value, int64(len(l.Elements)))
+		if !ok {
 			return NULL
 		}
 
 		return l.Elements[idx]
 
+	case *object.String:
+		i, ok := index.(*object.Integer)
+		if !ok {
+			return newTypeError("index operator not supported: %s[%s]", left.Type(), index.Type())
+		}
+
+		runes := []rune(l.Value)
+		idx, ok := normalizeIndex(i.Value, int64(len(runes)))
+		if !ok {
+			return NULL
+		}
+
+		return &object.String{Value: string(runes[idx])}
+
+	case *object.Range:
+		i, ok := index.(*object.Integer)
+		if !ok {
+			return newTypeError("index operator not supported: %s[%s]", left.Type(), index.Type())
+		}
+
+		idx, ok := normalizeIndex(i.Value, l.Len())
+		if !ok {
+			return NULL
+		}
+
+		return &object.Integer{Value: l.At(idx)}
+
+	case *object.Module:
+		name, ok := index.(*object.String)
+		if !ok {
+			return newTypeError("index operator not supported: %s[%s]", left.Type(), index.Type())
+		}
+
+		return evalModuleMember(l, name.Value)
+
 	case *object.Hash:
-		idx := index.(object.Hashable)
-		if v, ok := l.Pairs[idx.HashKey()]; ok {
+		key, ok := object.HashKeyOf(index)
+		if !ok {
+			return newTypeError("unusable as hash key: %s", index.Type())
+		}
+
+		if v, ok := l.Get(key); ok {
 			return v.Value
 		}
 
@@ -142,56 +257,480 @@ func evalIndexExpression(left object.Object, index object.Object) object.Object
 	return NULL
 }
 
+// evalChain evaluates a member, index, slice or call expression, and
+// reports whether a ?. in it short-circuited. A ?. that finds a null or a
+// missing member makes the rest of the chain null without evaluating it, so
+// {"a": 1}?.b.c is null.
+func evalChain(node ast.Expression, env *object.Environment) (object.Object, bool) {
+	switch node := node.(type) {
+	case *ast.MemberExpression:
+		left, short := evalChain(node.Left, env)
+		if short || isAbrupt(left) {
+			return left, short
+		}
+
+		member := evalMemberExpression(left, node.Member.Value, node.Optional)
+		if member == nil {
+			return NULL, true
+		}
+
+		return member, false
+
+	case *ast.IndexExpression:
+		left, short := evalChain(node.Left, env)
+		if short || isAbrupt(left) {
+			return left, short
+		}
+
+		index := Eval(node.Index, env)
+		if isAbrupt(index) {
+			return index, false
+		}
+
+		return evalIndexExpression(left, index), false
+
+	case *ast.SliceExpression:
+		left, short := evalChain(node.Left, env)
+		if short || isAbrupt(left) {
+			return left, short
+		}
+
+		return evalSliceExpression(node, left, env), false
+
+	case *ast.CallExpression:
+		if isCallTo(node, "quote") {
+			if len(node.Arguments) != 1 {
+				return newArgumentError("wrong number of arguments, got=%d, want=1", len(node.Arguments)), false
+			}
+			return quote(node.Arguments[0], env), false
+		}
+
+		fn, short := evalChain(node.Function, env)
+		if short || isAbrupt(fn) {
+			return fn, short
+		}
+
+		args := evalExpressions(node.Arguments, env)
+		if len(args) == 1 && isAbrupt(args[0]) {
+			return args[0], false
+		}
+
+		result := applyFunction(env.Context(), fn, args)
+		if err, ok := result.(*object.Error); ok {
+			err.Stack = append(err.Stack, node.Function.String())
+		}
+
+		return result, false
+	}
+
+	return Eval(node, env), false
+}
+
+// evalMemberExpression looks up a module member or a hash's string key,
+// falling back to the methods registered for the value's type. A missing
+// member is an error, except through ?., which returns nil so that
+// evalChain can short-circuit.
+func evalMemberExpression(left object.Object, name string, optional bool) object.Object {
+	switch l := left.(type) {
+	case *object.Module:
+		if member, ok := l.Members[name]; ok {
+			return member
+		}
+
+	case *object.Hash:
+		key := &object.String{Value: name}
+		if pair, ok := l.Get(key.HashKey()); ok {
+			return pair.Value
+		}
+
+	case *object.Instance:
+		if idx := l.Struct.FieldIndex(name); idx >= 0 {
+			return l.Values[idx]
+		}
+
+	case *object.ClassObject:
+		if member := evalObjectMember(l, name); member != nil {
+			return member
+		}
+
+	case *object.Super:
+		if fn, owner := l.Class.FindMethod(name); fn != nil {
+			return bindMethod(l.Self, fn, owner)
+		}
+
+	case *object.Enum:
+		if member := evalEnumMember(l, name); member != nil {
+			return member
+		}
+
+	case *object.EnumValue:
+		if idx := l.Variant.FieldIndex(name); idx >= 0 {
+			return l.Values[idx]
+		}
+
+	case *object.Exception:
+		if field, ok := exceptionField(l, name); ok {
+			return field
+		}
+	}
+
+	if method := boundMethod(left, name); method != nil {
+		return method
+	}
+
+	if optional {
+		return nil
+	}
+
+	switch l := left.(type) {
+	case *object.Module:
+		return evalModuleMember(l, name)
+	case *object.Hash:
+		return newAttributeError("hash has no member %s", name)
+	case *object.Instance:
+		return newAttributeError("struct %s has no field %s", l.Struct.Name, name)
+	case *object.ClassObject:
+		return newAttributeError("%s object has no member %s", l.Class.Name, name)
+	case *object.Super:
+		return newAttributeError("superclass %s has no method %s", l.Class.Name, name)
+	case *object.Enum:
+		return newAttributeError("enum %s has no variant %s", l.Name, name)
+	case *object.EnumValue:
+		return newAttributeError("variant %s.%s has no field %s", l.Variant.Enum.Name, l.Variant.Name, name)
+	default:
+		return newTypeError("member access not supported: %s.%s", left.Type(), name)
+	}
+}
+
+func evalMemberAssignment(left object.Object, name string, val object.Object) object.Object {
+	switch l := left.(type) {
+	case *object.Hash:
+		key := &object.String{Value: name}
+		l.Set(key.HashKey(), object.HashPair{Key: key, Value: val})
+		return val
+
+	case *object.Instance:
+		idx := l.Struct.FieldIndex(name)
+		if idx < 0 {
+			return newAttributeError("struct %s has no field %s", l.Struct.Name, name)
+		}
+
+		l.Values[idx] = val
+		return val
+
+	case *object.ClassObject:
+		l.Set(name, val)
+		return val
+
+	default:
+		return newTypeError("member assignment not supported: %s.%s", left.Type(), name)
+	}
+}
+
+func evalModuleMember(module *object.Module, name string) object.Object {
+	if member, ok := module.Members[name]; ok {
+		return member
+	}
+
+	return newAttributeError("module %s has no member %s", module.Name, name)
+}
+
+// normalizeIndex resolves a negative index against the end of a sequence of
+// the given length and reports whether the result is in bounds.
+func normalizeIndex(idx, length int64) (int64, bool) {
+	if idx < 0 {
+		idx += length
+	}
+
+	return idx, idx >= 0 && idx < length
+}
+
+func evalSliceExpression(node *ast.SliceExpression, left object.Object, env *object.Environment) object.Object {
+	var start, end object.Object
+	if node.Start != nil {
+		start = Eval(node.Start, env)
+		if isAbrupt(start) {
+			return start
+		}
+	}
+
+	if node.End != nil {
+		end = Eval(node.End, env)
+		if isAbrupt(end) {
+			return end
+		}
+	}
+
+	switch l := left.(type) {
+	case *object.Array:
+		lo, hi, err := sliceBounds(start, end, int64(len(l.Elements)))
+		if err != nil {
+			return err
+		}
+
+		elements := make([]object.Object, hi-lo)
+		copy(elements, l.Elements[lo:hi])
+		return &object.Array{Elements: elements}
+
+	case *object.Tuple:
+		lo, hi, err := sliceBounds(start, end, int64(len(l.Elements)))
+		if err != nil {
+			return err
+		}
+
+		elements := make([]object.Object, hi-lo)
+		copy(elements, l.Elements[lo:hi])
+		return &object.Tuple{Elements: elements}
+
+	case *object.String:
+		runes := []rune(l.Value)
+		lo, hi, err := sliceBounds(start, end, int64(len(runes)))
+		if err != nil {
+			return err
+		}
+
+		return &object.String{Value: string(runes[lo:hi])}
+
+	case *object.Range:
+		lo, hi, err := sliceBounds(start, end, l.Len())
+		if err != nil {
+			return err
+		}
+
+		return &object.Range{Start: l.At(lo), End: l.At(hi)}
+
+	default:
+		return newTypeError("slice operator not supported: %s", left.Type())
+	}
+}
+
+// sliceBounds converts optional, possibly negative slice bounds into a
+// [lo, hi) pair clamped to a sequence of the given length.
+func sliceBounds(start, end object.Object, length int64) (int64, int64, *object.Error) {
+	lo, hi := int64(0), length
+
+	if start != nil {
+		i, ok := start.(*object.Integer)
+		if !ok {
+			return 0, 0, newTypeError("slice index must be INTEGER, got %s", start.Type())
+		}
+		lo = clampIndex(i.Value, length)
+	}
+
+	if end != nil {
+		i, ok := end.(*object.Integer)
+		if !ok {
+			return 0, 0, newTypeError("slice index must be INTEGER, got %s", end.Type())
+		}
+		hi = clampIndex(i.Value, length)
+	}
+
+	if lo > hi {
+		lo = hi
+	}
+
+	return lo, hi, nil
+}
+
+func clampIndex(idx, length int64) int64 {
+	if idx < 0 {
+		idx += length
+	}
+
+	if idx < 0 {
+		return 0
+	}
+
+	if idx > length {
+		return length
+	}
+
+	return idx
+}
+
+func evalAssignExpression(node *ast.AssignExpression, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isAbrupt(val) {
+		return val
+	}
+
+	// compound forms such as += apply the bare operator to the current value
+	operator := strings.TrimSuffix(node.Operator, "=")
+
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		if operator != "" {
+			current, ok := env.Get(target.Value)
+			if !ok {
+				return newNameError("identifier not found: %s", target.Value)
+			}
+
+			val = evalInfixExpression(operator, current, val)
+			if isAbrupt(val) {
+				return val
+			}
+		}
+
+		if _, ok := env.Assign(target.Value, val); !ok {
+			return newNameError("identifier not found: %s", target.Value)
+		}
+
+		return val
+
+	case *ast.IndexExpression:
+		left := Eval(target.Left, env)
+		if isAbrupt(left) {
+			return left
+		}
+
+		index := Eval(target.Index, env)
+		if isAbrupt(index) {
+			return index
+		}
+
+		if operator != "" {
+			current := evalIndexExpression(left, index)
+			if isAbrupt(current) {
+				return current
+			}
+
+			val = evalInfixExpression(operator, current, val)
+			if isAbrupt(val) {
+				return val
+			}
+		}
+
+		return evalIndexAssignment(left, index, val)
+
+	case *ast.MemberExpression:
+		left := Eval(target.Left, env)
+		if isAbrupt(left) {
+			return left
+		}
+
+		if operator != "" {
+			current := evalMemberExpression(left, target.Member.Value, false)
+			if isAbrupt(current) {
+				return current
+			}
+
+			val = evalInfixExpression(operator, current, val)
+			if isAbrupt(val) {
+				return val
+			}
+		}
+
+		return evalMemberAssignment(left, target.Member.Value, val)
+
+	default:
+		return newError("invalid assignment target: %s", node.Target.String())
+	}
+}
+
+func evalIndexAssignment(left, index, val object.Object) object.Object {
+	switch l := left.(type) {
+	case *object.Array:
+		i, ok := index.(*object.Integer)
+		if !ok {
+			return newTypeError("index operator not supported: %s[%s]", left.Type(), index.Type())
+		}
+
+		idx, ok := normalizeIndex(i.Value, int64(len(l.Elements)))
+		if !ok {
+			return newIndexError("index out of range: %d, array length %d", i.Value, len(l.Elements))
+		}
+
+		l.Elements[idx] = val
+		return val
+
+	case *object.Hash:
+		key, ok := object.HashKeyOf(index)
+		if !ok {
+			return newTypeError("unusable as hash key: %s", index.Type())
+		}
+
+		l.Set(key, object.HashPair{Key: index, Value: val})
+		return val
+
+	default:
+		return newTypeError("index assignment not supported: %s", left.Type())
+	}
+}
+
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	for k, v := range node.Pairs {
+	for _, k := range node.Keys {
 		key := Eval(k, env)
-		if isError(key) {
+		if isAbrupt(key) {
 			return key
 		}
 
-		hashkey, ok := key.(object.Hashable)
+		hashkey, ok := object.HashKeyOf(key)
 		if !ok {
-			return newError("unusable as hash key: %s", key.Type())
+			return newTypeError("unusable as hash key: %s", key.Type())
 		}
 
-		value := Eval(v, env)
-		if isError(value) {
+		value := Eval(node.Pairs[k], env)
+		if isAbrupt(value) {
 			return value
 		}
 
-		pairs[hashkey.HashKey()] = object.HashPair{
+		hash.Set(hashkey, object.HashPair{
 			Key:   key,
 			Value: value,
-		}
+		})
 	}
 
-	return &object.Hash{
-		Pairs: pairs,
-	}
+	return hash
 }
 
 func evalArrayLiteral(node *ast.ArrayLiteral, env *object.Environment) object.Object {
 	elements := evalExpressions(node.Elements, env)
-	if len(elements) == 1 && isError(elements[0]) {
+	if len(elements) == 1 && isAbrupt(elements[0]) {
 		return elements[0]
 	}
 
 	return &object.Array{Elements: elements}
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+func applyFunction(ctx *object.Context, fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
+		if len(args) < len(fn.Parameters) {
+			return newArgumentError("wrong number of arguments, got=%d, want=%d", len(args), len(fn.Parameters))
+		}
+
 		extendedEnv := extendFunctionEnv(fn, args)
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 
 	case *object.Builtin:
-		return fn.Fn(args...)
+		return fn.Fn(ctx, args...)
+
+	case *object.Struct:
+		if len(args) != len(fn.Fields) {
+			return newArgumentError("wrong number of arguments, got=%d, want=%d", len(args), len(fn.Fields))
+		}
+
+		values := make([]object.Object, len(args))
+		copy(values, args)
+		return &object.Instance{Struct: fn, Values: values}
+
+	case *object.Class:
+		return instantiate(ctx, fn, args)
+
+	case *object.Variant:
+		if len(args) != len(fn.Fields) {
+			return newArgumentError("wrong number of arguments, got=%d, want=%d", len(args), len(fn.Fields))
+		}
+
+		values := make([]object.Object, len(args))
+		copy(values, args)
+		return &object.EnumValue{Variant: fn, Values: values}
+
 	default:
-		return newError("not a function: %s", fn.Type())
+		return newTypeError("not a function: %s", fn.Type())
 
 	}
 }
@@ -207,8 +746,13 @@ func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Enviro
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
-	if resultVal, ok := obj.(*object.ReturnValue); ok {
-		return resultVal
+	switch obj := obj.(type) {
+	case *object.ReturnValue:
+		return obj.Value
+	case *object.Break:
+		return newError("break outside loop")
+	case *object.Continue:
+		return newError("continue outside loop")
 	}
 
 	return obj
@@ -220,7 +764,7 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	for _, e := range exps {
 		evaluated := Eval(e, env)
 
-		if isError(evaluated) {
+		if isAbrupt(evaluated) {
 			return []object.Object{evaluated}
 		}
 
@@ -239,7 +783,11 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 		return builtin
 	}
 
-	return newError("identifier not found: " + node.Value)
+	if module, ok := modules[node.Value]; ok {
+		return module
+	}
+
+	return newNameError("identifier not found: %s", node.Value)
 }
 
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
@@ -249,8 +797,8 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 		result = Eval(statement, env)
 
 		if result != nil {
-			rt := result.Type()
-			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+			switch result.Type() {
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
 				return result
 			}
 		}
@@ -271,6 +819,12 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 
 		case *object.Error:
 			return result
+
+		case *object.Break:
+			return newError("break outside loop")
+
+		case *object.Continue:
+			return newError("continue outside loop")
 		}
 	}
 
@@ -279,7 +833,7 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 
 func evalIfExpression(node *ast.IfExpression, env *object.Environment) object.Object {
 	cond := Eval(node.Condition, env)
-	if isError(cond) {
+	if isAbrupt(cond) {
 		return cond
 	}
 
@@ -294,6 +848,118 @@ func evalIfExpression(node *ast.IfExpression, env *object.Environment) object.Ob
 	return NULL
 }
 
+func evalWhileExpression(node *ast.WhileExpression, env *object.Environment) object.Object {
+	for {
+		cond := Eval(node.Condition, env)
+		if isAbrupt(cond) {
+			return cond
+		}
+
+		if !isTruth(cond) {
+			return NULL
+		}
+
+		result := Eval(node.Body, env)
+		if result != nil {
+			switch result.Type() {
+			case object.BREAK_OBJ:
+				return NULL
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ:
+				return result
+			}
+		}
+	}
+}
+
+func evalForExpression(node *ast.ForExpression, env *object.Environment) object.Object {
+	iterable := Eval(node.Iterable, env)
+	if isAbrupt(iterable) {
+		return iterable
+	}
+
+	_, isHash := iterable.(*object.Hash)
+
+	var result object.Object = NULL
+	err := forEach(iterable, func(key, value object.Object) bool {
+		// every iteration gets its own scope so closures capture that
+		// iteration's variables
+		loopEnv := object.NewEnclosedEnvironment(env)
+		switch {
+		case node.Key != nil:
+			loopEnv.Set(node.Key.Value, key)
+			loopEnv.Set(node.Value.Value, value)
+		case isHash:
+			loopEnv.Set(node.Value.Value, key)
+		default:
+			loopEnv.Set(node.Value.Value, value)
+		}
+
+		evaluated := Eval(node.Body, loopEnv)
+		if evaluated != nil {
+			switch evaluated.Type() {
+			case object.BREAK_OBJ:
+				return false
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ:
+				result = evaluated
+				return false
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	return result
+}
+
+// forEach calls fn with the index (or hash key) and value of each element of
+// an iterable object, stopping early when fn returns false.
+func forEach(iterable object.Object, fn func(key, value object.Object) bool) *object.Error {
+	switch it := iterable.(type) {
+	case *object.Array:
+		for i, e := range it.Elements {
+			if !fn(&object.Integer{Value: int64(i)}, e) {
+				return nil
+			}
+		}
+
+	case *object.Tuple:
+		for i, e := range it.Elements {
+			if !fn(&object.Integer{Value: int64(i)}, e) {
+				return nil
+			}
+		}
+
+	case *object.String:
+		for i, r := range []rune(it.Value) {
+			if !fn(&object.Integer{Value: int64(i)}, &object.String{Value: string(r)}) {
+				return nil
+			}
+		}
+
+	case *object.Range:
+		for i := int64(0); i < it.Len(); i++ {
+			if !fn(&object.Integer{Value: i}, &object.Integer{Value: it.At(i)}) {
+				return nil
+			}
+		}
+
+	case *object.Hash:
+		for _, pair := range it.Ordered() {
+			if !fn(pair.Key, pair.Value) {
+				return nil
+			}
+		}
+
+	default:
+		return newTypeError("not iterable: %s", iterable.Type())
+	}
+
+	return nil
+}
+
 func isTruth(obj object.Object) bool {
 	switch obj {
 	case NULL:
@@ -309,19 +975,37 @@ func isTruth(obj object.Object) bool {
 
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
+	case operator == "==":
+		return nativeBoolToBooleanObject(object.Equal(left, right))
+	case operator == "!=":
+		return nativeBoolToBooleanObject(!object.Equal(left, right))
+	case isNumber(left) && isNumber(right) && (left.Type() == object.FLOAT_OBJ || right.Type() == object.FLOAT_OBJ):
+		return evalFloatInfixExpression(operator, toFloat(left), toFloat(right))
+	case isTemporal(left) || isTemporal(right):
+		return evalTemporalInfixExpression(operator, left, right)
 	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	case left.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
 	case left.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
-	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
-	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// newRange builds the range start..end or start..<end, rejecting one with
+// more integers than an int64 can count, such as 0..9223372036854775807.
+func newRange(start, end int64, operator string) object.Object {
+	inclusive := operator == ".."
+	if end > start {
+		span := uint64(end) - uint64(start)
+		if span > math.MaxInt64 || inclusive && span == math.MaxInt64 {
+			return newError("range too large: %d%s%d", start, operator, end)
+		}
 	}
+
+	return &object.Range{Start: start, End: end, Inclusive: inclusive}
 }
 
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
@@ -330,8 +1014,12 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
 	switch operator {
 	case "+":
 		return &object.String{Value: leftVal + rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -344,6 +1032,9 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	case "-":
 		return &object.Integer{Value: leftVal - rightVal}
 	case "/":
+		if rightVal == 0 {
+			return newZeroDivisionError("division by zero")
+		}
 		return &object.Integer{Value: leftVal / rightVal}
 	case "*":
 		return &object.Integer{Value: leftVal * rightVal}
@@ -355,10 +1046,116 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 		return nativeBoolToBooleanObject(leftVal == rightVal)
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
+	case "..", "..<":
+		return newRange(leftVal, rightVal, operator)
+	default:
+		return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+}
+
+func evalFloatInfixExpression(operator string, leftVal, rightVal float64) object.Object {
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "/":
+		return &object.Float{Value: leftVal / rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypeError("unknown operator: %s %s %s", object.FLOAT_OBJ, operator, object.FLOAT_OBJ)
+	}
+}
+
+func isTemporal(obj object.Object) bool {
+	return obj.Type() == object.TIME_OBJ || obj.Type() == object.DURATION_OBJ
+}
+
+// evalTemporalInfixExpression implements time arithmetic: times and
+// durations add and subtract, durations scale by integers, and values of
+// the same kind compare.
+func evalTemporalInfixExpression(operator string, left, right object.Object) object.Object {
+	switch l := left.(type) {
+	case *object.Time:
+		switch r := right.(type) {
+		case *object.Duration:
+			switch operator {
+			case "+":
+				return &object.Time{Value: l.Value.Add(r.Value)}
+			case "-":
+				return &object.Time{Value: l.Value.Add(-r.Value)}
+			}
+		case *object.Time:
+			switch operator {
+			case "-":
+				return &object.Duration{Value: l.Value.Sub(r.Value)}
+			case "<":
+				return nativeBoolToBooleanObject(l.Value.Before(r.Value))
+			case ">":
+				return nativeBoolToBooleanObject(l.Value.After(r.Value))
+			}
+		}
+
+	case *object.Duration:
+		switch r := right.(type) {
+		case *object.Time:
+			if operator == "+" {
+				return &object.Time{Value: r.Value.Add(l.Value)}
+			}
+		case *object.Duration:
+			switch operator {
+			case "+":
+				return &object.Duration{Value: l.Value + r.Value}
+			case "-":
+				return &object.Duration{Value: l.Value - r.Value}
+			case "<":
+				return nativeBoolToBooleanObject(l.Value < r.Value)
+			case ">":
+				return nativeBoolToBooleanObject(l.Value > r.Value)
+			}
+		case *object.Integer:
+			switch operator {
+			case "*":
+				return &object.Duration{Value: l.Value * time.Duration(r.Value)}
+			case "/":
+				if r.Value == 0 {
+					return newZeroDivisionError("division by zero")
+				}
+				return &object.Duration{Value: l.Value / time.Duration(r.Value)}
+			}
+		}
+
+	case *object.Integer:
+		if r, ok := right.(*object.Duration); ok && operator == "*" {
+			return &object.Duration{Value: time.Duration(l.Value) * r.Value}
+		}
+	}
+
+	if left.Type() != right.Type() {
+		return newTypeError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	return newTypeError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.FLOAT_OBJ
+}
+
+// toFloat widens an Integer or Float to a float64; callers check isNumber
+// first.
+func toFloat(obj object.Object) float64 {
+	if i, ok := obj.(*object.Integer); ok {
+		return float64(i.Value)
 	}
 
+	return obj.(*object.Float).Value
 }
 
 func evalPrefixExpression(operator string, right object.Object) object.Object {
@@ -368,13 +1165,21 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 	case "-":
 		return evalMinuPrefixOperatorExpression(right)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newTypeError("unknown operator: %s%s", operator, right.Type())
 	}
 }
 
 func evalMinuPrefixOperatorExpression(right object.Object) object.Object {
+	if f, ok := right.(*object.Float); ok {
+		return &object.Float{Value: -f.Value}
+	}
+
+	if d, ok := right.(*object.Duration); ok {
+		return &object.Duration{Value: -d.Value}
+	}
+
 	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: -%s", right.Type())
+		return newTypeError("unknown operator: -%s", right.Type())
 	}
 
 	value := right.(*object.Integer).Value
@@ -405,7 +1210,7 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 }
 
 func newError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: "Error"}
 }
 
 func isError(obj object.Object) bool {
@@ -415,3 +1220,19 @@ func isError(obj object.Object) bool {
 
 	return false
 }
+
+// isAbrupt reports whether obj ends the evaluation of whatever expression
+// produced it: an error, a value returned out of the middle of one, as the
+// ? operator does, or a break or continue inside it.
+func isAbrupt(obj object.Object) bool {
+	if obj == nil {
+		return false
+	}
+
+	switch obj.Type() {
+	case object.ERROR_OBJ, object.RETURN_VALUE_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+		return true
+	}
+
+	return false
+}
@@ -1,8 +1,15 @@
 package evaluator
 
 import (
+	"bytes"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/elsonwu/monkey-go/ast"
 	"github.com/elsonwu/monkey-go/lexer"
 	"github.com/elsonwu/monkey-go/object"
 	"github.com/elsonwu/monkey-go/parser"
@@ -44,6 +51,14 @@ func testEval(input string) object.Object {
 	return Eval(program, env)
 }
 
+func testEvalWithContext(input string, ctx *object.Context) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	env := object.NewEnvironmentWithContext(ctx)
+	return Eval(program, env)
+}
+
 func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
 	result, ok := obj.(*object.Integer)
 	if !ok {
@@ -439,3 +454,2043 @@ func TestHashIndex(t *testing.T) {
 		testIntegerObject(t, result, tt.expected)
 	}
 }
+
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let a = 1; a = 2; a;", 2},
+		{"let a = 1; a += 4; a;", 5},
+		{"let a = 10; a -= 4; a;", 6},
+		{"let a = 3; a *= 4; a;", 12},
+		{"let a = 12; a /= 4; a;", 3},
+		{"let a = 1; let b = 1; a = b = 7; a + b;", 14},
+		{"let a = 1; let f = fn() { a = 5; }; f(); a;", 5},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestIndexAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1, 2, 3]; a[0] = 9; a;", "[9, 2, 3]"},
+		{"let a = [1, 2, 3]; a[1] += 5; a;", "[1, 7, 3]"},
+		{"let a = [[1], [2]]; a[1][0] *= 10; a;", "[[1], [20]]"},
+		{`let h = {"a": 1}; h["a"] = 2; h["a"];`, "2"},
+		{`let h = {"a": 1}; h["b"] = 3; h["b"];`, "3"},
+		{`let h = {"a": 1}; h["a"] -= 3; h["a"];`, "-2"},
+		{`let h = {}; h[1] = "one"; h[1];`, `"one"`},
+		{"let a = [1]; a[0] = 2;", "2"},
+		{"let a = [1, 2, 3]; a[-1] = 0; a;", "[1, 2, 0]"},
+		{"let a = [1, 2]; a[0] = a;", "[[...], 2]"},
+		{`let h = {"n": 1}; h["self"] = h; h;`, `{"n":1, "self":{...}}`},
+		{`let a = [1]; let h = {"a": a}; a[0] = h; [a, a];`, `[[{"a":[...]}], [{"a":[...]}]]`},
+		{"let a = [1]; a[0] = ok((a,)); a;", "[ok(([...],))]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestAssignErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1, 2]; a[2] = 3;", "index out of range: 2, array length 2"},
+		{"let a = [1, 2]; a[-3] = 3;", "index out of range: -3, array length 2"},
+		{`let a = [1, 2]; a["x"] = 3;`, "index operator not supported: ARRAY[STRING]"},
+		{`let h = {}; h[fn(x) { x }] = 1;`, "unusable as hash key: FUNCTION"},
+		{`let h = {}; h["a"] += 1;`, "type mismatch: NULL + INTEGER"},
+		{`let s = "abc"; s[0] = "x";`, "index assignment not supported: STRING"},
+		{"b = 1;", "identifier not found: b"},
+		{"b += 1;", "identifier not found: b"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestWhileExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let i = 0; while (i < 10) { i += 1 }; i;", 10},
+		{"let i = 0; while (true) { i += 1; if (i > 4) { break } }; i;", 5},
+		{"let i = 0; let s = 0; while (i < 5) { i += 1; if (i == 2) { continue } s += i }; s;", 13},
+		{"let f = fn() { let i = 0; while (true) { i += 1; if (i == 3) { return i } } }; f() + 1;", 4},
+		{"let i = 0; while (i < 100000) { i += 1 }; i;", 100000},
+		{"let i = 0; while (i < 5) { i += 1; let y = if (i == 2) { break; }; }; i;", 2},
+		{"let i = 0; let s = 0; while (i < 5) { i += 1; s += if (i == 2) { continue; } else { i }; }; s;", 13},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestForExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let s = 0; for (x in [1, 2, 3]) { s += x }; s;", "6"},
+		{"let s = 0; for (i, x in [10, 20, 30]) { s += i * x }; s;", "80"},
+		{`let s = ""; for (c in "abc") { s = c + s }; s;`, `"cba"`},
+		{`let s = 0; for (k, v in {"a": 1, "b": 2}) { s += v }; s;`, "3"},
+		{`let s = ""; for (k in {"a": 1}) { s = k }; s;`, `"a"`},
+		{"let s = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { break } s += x }; s;", "3"},
+		{"let s = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { continue } s += x }; s;", "7"},
+		{"let f = fn(a) { for (x in a) { if (x > 1) { return x } } }; f([1, 5, 9]);", "5"},
+		{"let fs = []; for (x in [1, 2]) { fs = push(fs, fn() { x }) }; fs[0]() + fs[1]();", "3"},
+		{"for (x in []) { x }", "null"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestLoopErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"break;", "break outside loop"},
+		{"continue;", "continue outside loop"},
+		{"let f = fn() { break; }; for (x in [1]) { f() }", "break outside loop"},
+		{"for (x in 5) { x }", "not iterable: INTEGER"},
+		{"while (true) { 1 + true }", "type mismatch: INTEGER + BOOLEAN"},
+		{"while (y) { 1 }", "identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestRangeExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1..10", "1..10"},
+		{"1..<10", "1..<10"},
+		{"let n = 3; 0..n * 2", "0..6"},
+		{"len(1..10)", "10"},
+		{"len(1..<10)", "9"},
+		{"len(5..1)", "0"},
+		{"len(5..<5)", "0"},
+		{"len(5..5)", "1"},
+		{"len(1..9223372036854775807)", "9223372036854775807"},
+		{"len(0..<9223372036854775807)", "9223372036854775807"},
+		{"len(0..9223372036854775807)", "ERROR: range too large: 0..9223372036854775807"},
+		{"len(-1..<9223372036854775807)", "ERROR: range too large: -1..<9223372036854775807"},
+		{"(1..10)[0]", "1"},
+		{"(1..10)[-1]", "10"},
+		{"(1..<10)[-1]", "9"},
+		{"(1..10)[10]", "null"},
+		{"(1..10)[2:4]", "3..<5"},
+		{"let s = 0; for (i in 1..100) { s += i }; s;", "5050"},
+		{"let s = 0; for (i in 0..<4) { s += i }; s;", "6"},
+		{"let s = 0; for (i in 0..1000000000) { if (i == 3) { break } s += i }; s;", "3"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4][:2]", "[1, 2]"},
+		{"[1, 2, 3, 4][2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][-2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:-1]", "[1, 2, 3]"},
+		{"[1, 2, 3, 4][:]", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][3:1]", "[]"},
+		{"[1, 2, 3, 4][-10:10]", "[1, 2, 3, 4]"},
+		{"let a = [1, 2, 3]; let b = a[:]; b[0] = 9; a;", "[1, 2, 3]"},
+		{`"hello"[1:3]`, `"el"`},
+		{`"hello"[:5]`, `"hello"`},
+		{`"hello"[-3:]`, `"llo"`},
+		{`"héllo"[1:2]`, `"é"`},
+		{`"hello"[0]`, `"h"`},
+		{`"hello"[-1]`, `"o"`},
+		{`"hello"[5]`, "null"},
+		{"[1, 2, 3][-1]", "3"},
+		{"[1, 2, 3][-3]", "1"},
+		{"[1, 2, 3][-4]", "null"},
+		{`len("héllo")`, "5"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestSliceErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[1, 2]["a":]`, "slice index must be INTEGER, got STRING"},
+		{`[1, 2][:true]`, "slice index must be INTEGER, got BOOLEAN"},
+		{`5[1:2]`, "slice operator not supported: INTEGER"},
+		{`{"a": 1}[0:1]`, "slice operator not supported: HASH"},
+		{`"abc"["a"]`, "index operator not supported: STRING[STRING]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map([1, 2, 3], fn(x) { x * 2 })", "[2, 4, 6]"},
+		{"map(1..3, fn(x) { x * x })", "[1, 4, 9]"},
+		{`map("ab", fn(c) { c + c })`, `["aa", "bb"]`},
+		{"map([], fn(x) { x })", "[]"},
+		{`map([[1], [2, 3]], len)`, "[1, 2]"},
+		{"filter([1, 2, 3, 4], fn(x) { x > 2 })", "[3, 4]"},
+		{"reduce([1, 2, 3, 4], fn(acc, x) { acc + x })", "10"},
+		{"reduce([1, 2, 3], fn(acc, x) { acc + x }, 10)", "16"},
+		{"reduce([], fn(acc, x) { acc + x })", "null"},
+		{"reduce([], fn(acc, x) { acc + x }, 0)", "0"},
+		{"find([1, 2, 3], fn(x) { x > 1 })", "2"},
+		{"find([1, 2, 3], fn(x) { x > 5 })", "null"},
+		{"any([1, 2, 3], fn(x) { x > 2 })", "true"},
+		{"any([1, 2, 3], fn(x) { x > 5 })", "false"},
+		{"any([false, 1])", "true"},
+		{"all([1, 2, 3], fn(x) { x > 0 })", "true"},
+		{"all([1, 2, 3], fn(x) { x > 1 })", "false"},
+		{"all([])", "true"},
+		{"sort([3, 1, 2])", "[1, 2, 3]"},
+		{`sort(["b", "c", "a"])`, `["a", "b", "c"]`},
+		{"sort([2.0, 1.0])", "[1.0, 2.0]"},
+		{"sort([3, 1.5, 2, 0.5])", "[0.5, 1.5, 2, 3]"},
+		{"sort([3, 1, 2], fn(a, b) { a > b })", "[3, 2, 1]"},
+		{"sort([3, 1, 2], fn(a, b) { b - a })", "[3, 2, 1]"},
+		{"let a = [2, 1]; sort(a); a;", "[2, 1]"},
+		{"zip([1, 2, 3], [4, 5])", "[[1, 4], [2, 5]]"},
+		{`zip([1], ["a"], [true])`, `[[1, "a", true]]`},
+		{"flatten([1, [2, [3]], 4])", "[1, 2, [3], 4]"},
+		{"flatten([1, [2, [3]], 4], 5)", "[1, 2, 3, 4]"},
+		{`uniq([1, 2, 1, "a", "a", true, 3, true])`, `[1, 2, "a", true, 3]`},
+		{"range(3)", "[0, 1, 2]"},
+		{"range(2, 5)", "[2, 3, 4]"},
+		{"range(5, 0, -2)", "[5, 3, 1]"},
+		{"range(0)", "[]"},
+		{"range(9223372036854775800, 9223372036854775807, 10)", "[9223372036854775800]"},
+		{"range(-9223372036854775805, -9223372036854775807, -3)", "[-9223372036854775805]"},
+		{"range(9223372036854775807, 9223372036854775803, -2)", "[9223372036854775807, 9223372036854775805]"},
+		{"range(5, 1)", "[]"},
+		{"len(map(0..<10, fn(x) { x }))", "10"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestCollectionBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map(1, fn(x) { x })", "argument to `map` not supported, want ARRAY, got INTEGER"},
+		{"map([1], 2)", "argument to `map` not supported, want FUNCTION, got INTEGER"},
+		{"map([1])", "wrong number of arguments, got=1, want=2"},
+		{"map([1], fn(x) { x + true })", "type mismatch: INTEGER + BOOLEAN"},
+		{"map([1], fn(x, y) { x })", "wrong number of arguments, got=1, want=2"},
+		{"filter([1], fn(x) { y })", "identifier not found: y"},
+		{`sort([1, "a"])`, "argument to `sort` not supported, mixed INTEGER and STRING"},
+		{"sort([true])", "argument to `sort` not supported, cannot order BOOLEAN"},
+		{`sort([1.5, "a"])`, "argument to `sort` not supported, mixed FLOAT and STRING"},
+		{`sort([1, 2], fn(a, b) { "x" })`, "comparator for `sort` must return BOOLEAN or INTEGER, got STRING"},
+		{"range(1, 2, 0)", "argument to `range` not supported, step must not be 0"},
+		{"range(-9223372036854775807, 9223372036854775807)", "argument to `range` not supported, 18446744073709551614 elements exceed the limit of 67108864"},
+		{"map(0..1000000000, fn(x) { x })", "argument to `map` not supported, 1000000001 elements exceed the limit of 67108864"},
+		{"zip([1])", "wrong number of arguments, got=1, want=2+"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestHashOrdering(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"c": 1, "a": 2, "b": 3, "d": 4}`, `{"c":1, "a":2, "b":3, "d":4}`},
+		{`let h = {"b": 1}; h["a"] = 2; h["b"] = 3; h;`, `{"b":3, "a":2}`},
+		{`let s = ""; for (k, v in {"z": 1, "y": 2, "x": 3}) { s += k }; s;`, `"zyx"`},
+		{`keys({"z": 1, 2: "y", true: 3})`, `["z", 2, true]`},
+		{`values({"z": 1, 2: "y", true: 3})`, `[1, "y", 3]`},
+	}
+
+	for _, tt := range tests {
+		// repeat to make sure the order does not depend on map iteration
+		for i := 0; i < 10; i++ {
+			evaluated := testEval(tt.input)
+			if evaluated.Inspect() != tt.expected {
+				t.Fatalf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`has({"a": 1}, "a")`, "true"},
+		{`has({"a": 1}, "b")`, "false"},
+		{`let h = {"a": 1, "b": 2, "c": 3}; delete(h, "b");`, "2"},
+		{`let h = {"a": 1, "b": 2, "c": 3}; delete(h, "b"); h;`, `{"a":1, "c":3}`},
+		{`let h = {"a": 1}; delete(h, "x");`, "null"},
+		{`let h = {"a": 1, "b": 2}; delete(h, "a"); h["a"] = 3; h;`, `{"b":2, "a":3}`},
+		{`merge({"a": 1, "b": 2}, {"b": 3, "c": 4})`, `{"a":1, "b":3, "c":4}`},
+		{`let a = {"a": 1}; merge(a, {"b": 2}); a;`, `{"a":1}`},
+		{`keys({})`, "[]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`keys([1])`, "argument to `keys` not supported, want HASH, got ARRAY"},
+		{`values(1, 2)`, "wrong number of arguments, got=2, want=1"},
+		{`has({}, [1])`, "unusable as hash key: ARRAY"},
+		{`delete("a", "a")`, "argument to `delete` not supported, want HASH, got STRING"},
+		{`merge({}, 1)`, "argument to `merge` not supported, want HASH, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestHashKeysDoNotCollide(t *testing.T) {
+	hash := object.NewHash()
+	for i := 0; i < 5000; i++ {
+		key := &object.String{Value: string(rune('a'+i%26)) + string(rune(i))}
+		hash.Set(key.HashKey(), object.HashPair{Key: key, Value: &object.Integer{Value: int64(i)}})
+	}
+
+	if hash.Len() != 5000 {
+		t.Fatalf("hash has wrong num of pairs, got=%d, want=5000", hash.Len())
+	}
+
+	for i := 0; i < 5000; i++ {
+		key := &object.String{Value: string(rune('a'+i%26)) + string(rune(i))}
+		pair, ok := hash.Get(key.HashKey())
+		if !ok {
+			t.Fatalf("no pair for key %q", key.Value)
+		}
+		testIntegerObject(t, pair.Value, int64(i))
+	}
+
+	// These two strings share a 64-bit FNV-1a digest, so they landed on the
+	// same entry back when string keys were hashed.
+	x := &object.String{Value: "ff79b9fb8c434520"}
+	y := &object.String{Value: "2291feab8caf180a"}
+	hx, hy := fnv.New64a(), fnv.New64a()
+	hx.Write([]byte(x.Value))
+	hy.Write([]byte(y.Value))
+	if hx.Sum64() != hy.Sum64() {
+		t.Fatalf("%q and %q no longer share an FNV-1a digest", x.Value, y.Value)
+	}
+
+	colliding := object.NewHash()
+	colliding.Set(x.HashKey(), object.HashPair{Key: x, Value: &object.Integer{Value: 1}})
+	colliding.Set(y.HashKey(), object.HashPair{Key: y, Value: &object.Integer{Value: 2}})
+	if colliding.Len() != 2 {
+		t.Fatalf("colliding keys share an entry, got=%d pairs, want=2", colliding.Len())
+	}
+	pair, ok := colliding.Get(x.HashKey())
+	if !ok {
+		t.Fatalf("no pair for key %q", x.Value)
+	}
+	testIntegerObject(t, pair.Value, 1)
+
+	a := (&object.String{Value: "a"}).HashKey()
+	one := (&object.Integer{Value: 1}).HashKey()
+	if a == (&object.String{Value: "b"}).HashKey() || a == one || one == TRUE.HashKey() {
+		t.Errorf("distinct keys share a HashKey")
+	}
+}
+
+func TestTuples(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`(1, "a", true)`, `(1, "a", true)`},
+		{"(1,)", "(1,)"},
+		{"()", "()"},
+		{"(1 + 1)", "2"},
+		{"(1, 2, 3)[1]", "2"},
+		{"(1, 2, 3)[-1]", "3"},
+		{"(1, 2, 3)[1:]", "(2, 3)"},
+		{"len((1, 2, 3))", "3"},
+		{"let s = 0; for (x in (1, 2, 3)) { s += x }; s;", "6"},
+		{"tuple([1, 2])", "(1, 2)"},
+		{`let cache = {}; cache[(1, "a")] = 10; cache[(1, "a")];`, "10"},
+		{`let cache = {(1, "a"): 10}; cache[(1, "b")];`, "null"},
+		{`let k = (1, (2, 3)); {k: "x"}[(1, (2, 3))];`, `"x"`},
+		{`{("a", "bc"): 1, ("ab", "c"): 2}[("ab", "c")]`, "2"},
+		{`{(1,): 1, ("1",): 2}[(1,)]`, "1"},
+		{`uniq([(1, 2), (1, 2), (2, 1)])`, "[(1, 2), (2, 1)]"},
+		{`{(1, 2): true}`, "{(1, 2):true}"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestTupleErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let t = (1, 2); t[0] = 3;", "index assignment not supported: TUPLE"},
+		{"{(1, [2]): 1}", "unusable as hash key: TUPLE"},
+		{"{[1, 2]: 1}", "unusable as hash key: ARRAY"},
+		{"let h = {}; h[(fn(x) { x },)] = 1;", "unusable as hash key: TUPLE"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestStructuralEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"[1, 2] == [1, 2]", true},
+		{"[1, 2] == [2, 1]", false},
+		{"[1, 2] != [1, 2, 3]", true},
+		{"[[1], [2, [3]]] == [[1], [2, [3]]]", true},
+		{"[] == []", true},
+		{`"abc" == "abc"`, true},
+		{`"abc" != "abd"`, true},
+		{`"abc" == "ab" + "c"`, true},
+		{`{"a": 1, "b": [2]} == {"b": [2], "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`{"a": 1} == {"b": 1}`, false},
+		{`{"a": 1} == {"a": 1, "b": 2}`, false},
+		{"(1, [2]) == (1, [2])", true},
+		{"let a = [1]; a[0] = a; a == a", true},
+		{"let a = [1]; a[0] = a; let b = [1]; b[0] = b; a == b", true},
+		{"let a = [1, 2]; a[0] = a; let b = [1, 3]; b[0] = b; a != b", true},
+		{`let h = {}; h["self"] = h; let g = {}; g["self"] = g; h == g`, true},
+		{"let a = [1]; let t = (a,); a[0] = t; [t] == [t]", true},
+		{"(1, 2) == [1, 2]", false},
+		{"1..3 == 1..<4", true},
+		{"1..3 == 1..4", false},
+		{"1 == true", false},
+		{`1 != "1"`, true},
+		{"let f = fn() { 1 }; f == f", true},
+		{"fn() { 1 } == fn() { 1 }", false},
+		{`"a" < "b"`, true},
+		{`"b" < "a"`, false},
+		{`"abc" > "abd"`, false},
+		{`"b" > "abc"`, true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if !testBooleanObject(t, evaluated, tt.expected) {
+			t.Errorf("wrong result for %q", tt.input)
+		}
+	}
+}
+
+func TestUniqUsesStructuralEquality(t *testing.T) {
+	evaluated := testEval("uniq([[1], [1], [2], [1]])")
+	if evaluated.Inspect() != "[[1], [2]]" {
+		t.Errorf("wrong result, got=%s", evaluated.Inspect())
+	}
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`split("a,b,c", ",")`, `["a", "b", "c"]`},
+		{`split("abc", "")`, `["a", "b", "c"]`},
+		{`join(["a", "b", "c"], "-")`, `"a-b-c"`},
+		{`join([], "-")`, `""`},
+		{`trim("  hi  ")`, `"hi"`},
+		{`trim("xxhixx", "x")`, `"hi"`},
+		{`upper("Hello")`, `"HELLO"`},
+		{`lower("Hello")`, `"hello"`},
+		{`contains("hello", "ell")`, "true"},
+		{`contains("hello", "xyz")`, "false"},
+		{`starts_with("hello", "he")`, "true"},
+		{`ends_with("hello", "he")`, "false"},
+		{`replace("a-b-c", "-", "+")`, `"a+b+c"`},
+		{`replace("a-b-c", "-", "+", 1)`, `"a+b-c"`},
+		{`index_of("héllo", "l")`, "2"},
+		{`index_of("hello", "z")`, "-1"},
+		{`repeat("ab", 3)`, `"ababab"`},
+		{`repeat("ab", 0)`, `""`},
+		{`pad_left("7", 3, "0")`, `"007"`},
+		{`pad_left("abc", 2)`, `"abc"`},
+		{`pad_right("ab", 5)`, `"ab   "`},
+		{`pad_right("ab", 5, "xy")`, `"abxyx"`},
+		{`chars("héy")`, `["h", "é", "y"]`},
+		{`format("%s is %d years", "Al", 30)`, `"Al is 30 years"`},
+		{`format("%5d|%-4s|%05d", 42, "ab", 7)`, `"   42|ab  |00007"`},
+		{`format("%x %t %q %%", 255, true, "hi")`, `"ff true "hi" %"`},
+		{`format("%v and %s", [1, "a"], {"k": 1})`, `"[1, "a"] and {"k":1}"`},
+		{`format("plain")`, `"plain"`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStringBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`split("a")`, "wrong number of arguments, got=1, want=2"},
+		{`split(1, ",")`, "argument to `split` not supported, want STRING, got INTEGER"},
+		{`join([1, 2], ",")`, "argument to `join` not supported, want ARRAY of STRING, got INTEGER element"},
+		{`upper([1])`, "argument to `upper` not supported, want STRING, got ARRAY"},
+		{`repeat("a", "b")`, "argument to `repeat` not supported, want INTEGER, got STRING"},
+		{`repeat("a", -1)`, "argument to `repeat` not supported, negative count -1"},
+		{`pad_left("a", 3, "")`, "argument to `pad_left` not supported, empty padding"},
+		{`repeat("ab", 9223372036854775807)`, "argument to `repeat` not supported, result longer than 1073741824 bytes"},
+		{`repeat("ab", 536870913)`, "argument to `repeat` not supported, result longer than 1073741824 bytes"},
+		{`pad_left("a", 9223372036854775807)`, "argument to `pad_left` not supported, result longer than 1073741824 bytes"},
+		{`pad_right("", 9223372036854775807, "xy")`, "argument to `pad_right` not supported, result longer than 1073741824 bytes"},
+		{`format("%d", "x")`, "format: %d not supported for STRING"},
+		{`format("%d %d", 1)`, "format: missing argument for %d"},
+		{`format("%d", 1, 2)`, "format: 1 unused arguments"},
+		{`format("%z", 1)`, "format: unknown verb %z"},
+		{`format("100%")`, `format: incomplete verb "%"`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestFloatExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1.5", "1.5"},
+		{"-2.5", "-2.5"},
+		{"1.5 + 1", "2.5"},
+		{"2 * 1.5", "3.0"},
+		{"1 / 4.0", "0.25"},
+		{"1.0 / 0", "+Inf"},
+		{"0.1 + 0.2 > 0.3", "true"},
+		{"1.5 < 1", "false"},
+		{"1 == 1.0", "true"},
+		{"2.0 != 2", "false"},
+		{"100000000.0", "100000000.0"},
+		{"0.0000001", "1e-07"},
+		{`format("%.2f|%6.1f|%g", 3.14159, 2, 0.5)`, `"3.14|   2.0|0.5"`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMathModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`math["PI"]`, "3.141592653589793"},
+		{`math["abs"](-3)`, "3"},
+		{`math["abs"](-3.5)`, "3.5"},
+		{`math["min"](3, 1.5, 2)`, "1.5"},
+		{`math["max"]([3, 7, 2])`, "7"},
+		{`math["pow"](2, 10)`, "1024"},
+		{`math["pow"](2, -1)`, "0.5"},
+		{`math["pow"](4, 0.5)`, "2.0"},
+		{`math["pow"](1, 100000000000)`, "1"},
+		{`math["pow"](-1, 100000000001)`, "-1"},
+		{`math["pow"](-2, 63)`, "-9223372036854775808"},
+		{`math["pow"](3, 39)`, "4052555153018976267"},
+		{`math["pow"](2, 64)`, "18446744073709552000.0"},
+		{`math["sqrt"](16)`, "4.0"},
+		{`math["floor"](2.7)`, "2"},
+		{`math["floor"](-2.5)`, "-3"},
+		{`math["ceil"](2.1)`, "3"},
+		{`math["round"](2.5)`, "3"},
+		{`math["round"](7)`, "7"},
+		{`math["floor"](-9223372036854775808.0)`, "-9223372036854775808"},
+		{`math["clamp"](15, 0, 10)`, "10"},
+		{`math["clamp"](-1, 0, 10)`, "0"},
+		{`math["clamp"](5, 0, 10)`, "5"},
+		{`math["sin"](0)`, "0.0"},
+		{`math["cos"](0)`, "1.0"},
+		{`math["atan2"](0, 1)`, "0.0"},
+		{`math["log"](1)`, "0.0"},
+		{`math["exp"](0)`, "1.0"},
+		{`let m = math; m["max"](1, 2)`, "2"},
+		{"math", "<module math>"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMathRandomIsReproducible(t *testing.T) {
+	input := `
+	math["seed"](42);
+	let xs = map(range(20), fn(i) { math["random"](100) });
+	let f = math["random"]();
+	let r = math["random"](5, 10);
+	let w = math["random"](-9223372036854775807, 9223372036854775807);
+	[xs, f < 1.0, r > 4, r < 10, !(w < -9223372036854775807), w < 9223372036854775807]
+	`
+
+	first := testEval(input).Inspect()
+	second := testEval(input).Inspect()
+	if first != second {
+		t.Fatalf("same seed gave different results:\n%s\n%s", first, second)
+	}
+
+	if !strings.HasSuffix(first, ", true, true, true, true, true]") {
+		t.Errorf("random values out of range: %s", first)
+	}
+}
+
+func TestMathRandomIsPerContext(t *testing.T) {
+	draw := `math["random"](1000000)`
+	first := testEvalWithContext(draw, object.NewContext(nil, nil, nil)).Inspect()
+
+	seeded := object.NewContext(nil, nil, nil)
+	testEvalWithContext(`math["seed"](7)`, seeded)
+
+	second := testEvalWithContext(draw, object.NewContext(nil, nil, nil)).Inspect()
+	if first != second {
+		t.Errorf("seeding one context changed another: %s, then %s", first, second)
+	}
+
+	other := testEvalWithContext(draw, seeded).Inspect()
+	if other == first {
+		t.Errorf("math.seed did not reseed its own context, got=%s", other)
+	}
+}
+
+func TestMathErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`math["nope"]`, "module math has no member nope"},
+		{`math[1]`, "index operator not supported: MODULE[INTEGER]"},
+		{`math["sqrt"]("x")`, "argument to `sqrt` not supported, want INTEGER or FLOAT, got STRING"},
+		{`math["min"]()`, "argument to `min` not supported, no values"},
+		{`math["clamp"](1, 5, 0)`, "argument to `clamp` not supported, lower bound 5 above upper bound 0"},
+		{`math["random"](0)`, "argument to `random` not supported, empty interval [0, 0)"},
+		{`math["random"](1.5)`, "argument to `random` not supported, want INTEGER, got FLOAT"},
+		{`math["floor"](math["sqrt"](-1))`, "argument to `floor` not supported, NaN is out of INTEGER range"},
+		{`math["ceil"](-math["log"](0))`, "argument to `ceil` not supported, +Inf is out of INTEGER range"},
+		{`math["round"](math["pow"](10.0, 30))`, "argument to `round` not supported, 1e+30 is out of INTEGER range"},
+		{`math["floor"](9223372036854775807.0)`, "argument to `floor` not supported, 9223372036854776000.0 is out of INTEGER range"},
+		{"1 / 0", "division by zero"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestJSONEncode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json_encode({"b": 1, "a": [true, false, 2.5]})`, `{"b":1,"a":[true,false,2.5]}`},
+		{`json_encode("a<b")`, `"a<b"`},
+		{`json_encode(if (false) { 1 })`, "null"},
+		{`json_encode((1, "x"))`, `[1,"x"]`},
+		{`json_encode({"a": [1]}, 2)`, "{\n  \"a\": [\n    1\n  ]\n}"},
+		{`json_encode([1], "--")`, "[\n--1\n]"},
+		{`let a = [1]; json_encode([a, a])`, "[[1],[1]]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String for %q. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if str.Value != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+
+	input := `json_decode(json_encode({"z": [1, 2.5, "s", true, {}]}))`
+	evaluated := testEval(input)
+	if evaluated.Inspect() != `{"z":[1, 2.5, "s", true, {}]}` {
+		t.Errorf("round trip changed the value, got=%s", evaluated.Inspect())
+	}
+}
+
+func TestJSONDecode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": 1, "a": {"y": null, "x": [1e3, -2, 0.5]}}`, `{"b":1, "a":{"y":null, "x":[1000.0, -2, 0.5]}}`},
+		{`"café"`, `"café"`},
+		{`[]`, "[]"},
+		{`{"a": 1} x`, "json_decode: unexpected data after top-level value"},
+		{`{"a": }`, "json_decode: missing value after object key"},
+		{``, "json_decode: EOF"},
+	}
+
+	for _, tt := range tests {
+		decoded := builtinJSONDecode(object.DefaultContext, &object.String{Value: tt.input})
+		got := decoded.Inspect()
+		if errObj, ok := decoded.(*object.Error); ok {
+			got = errObj.Message
+		}
+
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+
+	decoded := builtinJSONDecode(object.DefaultContext, &object.String{Value: `{"a": [1, 2]}`}).(*object.Hash)
+	pair, ok := decoded.Get((&object.String{Value: "a"}).HashKey())
+	if !ok || pair.Value.Inspect() != "[1, 2]" {
+		t.Errorf("decoded hash is not indexable by string key, got=%v", pair.Value)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json_encode(fn(x) { x })`, "json_encode: unsupported value FUNCTION"},
+		{`json_encode([len])`, "json_encode: unsupported value BULITIN"},
+		{`json_encode({1: 2})`, "json_encode: hash key must be STRING, got INTEGER"},
+		{`json_encode([1], -1)`, "json_encode: indent must be non-negative"},
+		{`let a = [1]; a[0] = a; json_encode(a)`, "json_encode: cyclic value"},
+		{`let h = {}; h["t"] = (1, [h]); json_encode(h)`, "json_encode: cyclic value"},
+		{`json_encode([1], 9223372036854775807)`, "argument to `json_encode` not supported, result longer than 1073741824 bytes"},
+		{`json_encode(math["INF"])`, "json_encode: unsupported value +Inf"},
+		{`json_encode(1, true)`, "argument to `json_encode` not supported, want INTEGER or STRING, got BOOLEAN"},
+		{`json_decode(1)`, "argument to `json_decode` not supported, want STRING, got INTEGER"},
+		{`json_decode()`, "wrong number of arguments, got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestRegexModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`regex["test"]("^\d+$", "123")`, "true"},
+		{`regex["test"]("^\d+$", "12a")`, "false"},
+		{`regex["match"]("(\w+)@(\w+)", "mail bob@example now")`, `["bob@example", "bob", "example"]`},
+		{`regex["match"]("a(x)?b", "ab")`, `["ab", null]`},
+		{`regex["match"]("z", "abc")`, "null"},
+		{`regex["groups"]("(?P<year>\d{4})-(?P<month>\d{2})", "on 2024-05-17")`, `{"year":"2024", "month":"05"}`},
+		{`regex["groups"]("(?P<year>\d{4})", "none")`, "null"},
+		{`regex["find_all"]("\d+", "a1 b22 c333")`, `["1", "22", "333"]`},
+		{`regex["find_all"]("\d+", "a1 b22 c333", 2)`, `["1", "22"]`},
+		{`regex["replace"]("(\w+)@(\w+)", "bob@example", "$2 at ${1}")`, `"example at bob"`},
+		{`regex["replace"]("\d+", "a1 b22", fn(m) { m + m })`, `"a11 b2222"`},
+		{`regex["split"]("\s*,\s*", "a , b,c")`, `["a", "b", "c"]`},
+		{`regex["split"](",", "a,b,c", 2)`, `["a", "b,c"]`},
+		{`let re = regex["compile"]("^[a-z]+$"); [re, regex["test"](re, "abc")]`, "[/^[a-z]+$/, true]"},
+		{`regex["compile"]("a+") == regex["compile"]("a+")`, "true"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestRegexErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`regex["compile"]("(a")`, "regex: error parsing regexp: missing closing ): `(a`"},
+		{`regex["test"]("(a", "a")`, "regex: error parsing regexp: missing closing ): `(a`"},
+		{`regex["test"](1, "a")`, "argument to `test` not supported, want REGEX or STRING, got INTEGER"},
+		{`regex["match"]("a", 1)`, "argument to `match` not supported, want STRING, got INTEGER"},
+		{`regex["find_all"]("a")`, "wrong number of arguments, got=1, want=2 or 3"},
+		{`regex["replace"]("a", "a", 1)`, "argument to `replace` not supported, want STRING or FUNCTION, got INTEGER"},
+		{`regex["replace"]("a", "a", fn(m) { 1 })`, "replacement for `replace` must return STRING, got INTEGER"},
+		{`regex["split"]("a", "a", "x")`, "argument to `split` not supported, want INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestRegexCacheIsBounded(t *testing.T) {
+	for i := 1; i <= regexCacheSize*2; i++ {
+		if _, err := compileRegex(strings.Repeat("a", i)); err != nil {
+			t.Fatalf("compileRegex returned error: %s", err.Message)
+		}
+	}
+
+	regexMu.Lock()
+	size := len(regexCache)
+	regexMu.Unlock()
+	if size > regexCacheSize {
+		t.Errorf("regex cache grew to %d entries, want at most %d", size, regexCacheSize)
+	}
+}
+
+func TestTimeModule(t *testing.T) {
+	fixed := time.Date(2024, time.May, 17, 9, 30, 0, 0, time.UTC)
+	ctx := object.NewContext(nil, nil, nil)
+	ctx.Clock = func() time.Time { return fixed }
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`time["now"]()`, "2024-05-17T09:30:00Z"},
+		{`time["now"]() + time["HOUR"] * 2`, "2024-05-17T11:30:00Z"},
+		{`time["now"]() - time["duration"]("90m")`, "2024-05-17T08:00:00Z"},
+		{`time["since"](time["date"](2024, 5, 16))`, "33h30m0s"},
+		{`time["date"](2024, 5, 20) - time["now"]()`, "62h30m0s"},
+		{`time["date"](2024, 2, 28, 23, 59, 59)`, "2024-02-28T23:59:59Z"},
+		{`time["parse"]("2024-05-17T09:30:00+02:00") < time["now"]()`, "true"},
+		{`time["parse"]("17/05/2024", "02/01/2006") == time["date"](2024, 5, 17)`, "true"},
+		{`time["format"](time["now"](), "Jan 2, 2006 15:04")`, `"May 17, 2024 09:30"`},
+		{`time["format"](time["now"]())`, `"2024-05-17T09:30:00Z"`},
+		{`time["unix"](time["from_unix"](1700000000))`, "1700000000"},
+		{`time["from_unix"](1.5)`, "1970-01-01T00:00:01.5Z"},
+		{`time["from_unix"](-1.5)`, "1969-12-31T23:59:58.5Z"},
+		{`time["from_unix"](20000000000)`, "2603-10-11T11:33:20Z"},
+		{`time["from_unix"](20000000000.25)`, "2603-10-11T11:33:20.25Z"},
+		{`time["unix"](time["from_unix"](9007199254740993))`, "9007199254740993"},
+		{`time["seconds"](time["MINUTE"] / 4)`, "15.0"},
+		{`-time["SECOND"] + time["MINUTE"]`, "59s"},
+		{`3 * time["SECOND"] > time["SECOND"]`, "true"},
+		{`time["duration"]("1h") == time["MINUTE"] * 60`, "true"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalWithContext(tt.input, ctx)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	evaluated := testEval(`time["now"]()`)
+	if evaluated.Inspect() == "2024-05-17T09:30:00Z" {
+		t.Errorf("another context's clock was used, got=%s", evaluated.Inspect())
+	}
+}
+
+func TestTimeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`time["now"]() + time["now"]()`, "unknown operator: TIME + TIME"},
+		{`time["now"]() + 1`, "type mismatch: TIME + INTEGER"},
+		{`time["SECOND"] / 0`, "division by zero"},
+		{`time["duration"]("soon")`, `time: invalid duration "soon"`},
+		{`time["parse"]("yesterday")`, `time: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`},
+		{`time["format"]("2024")`, "argument to `format` not supported, want TIME, got STRING"},
+		{`time["date"](2024, 5)`, "wrong number of arguments, got=2, want=3 to 6"},
+		{`time["seconds"](5)`, "argument to `seconds` not supported, want DURATION, got INTEGER"},
+		{`time["from_unix"](math["INF"])`, "argument to `from_unix` not supported, +Inf is out of range"},
+		{`time["from_unix"](10000000000000000000.0)`, "argument to `from_unix` not supported, 10000000000000000000.0 is out of range"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestOutputBuiltins(t *testing.T) {
+	tests := []struct {
+		input  string
+		stdout string
+		stderr string
+	}{
+		{`puts("a", 1)`, "\"a\"\n1\n", ""},
+		{`print("x =", 1); print([true])`, "x = 1[true]", ""},
+		{`eprint("oops", "!")`, "", "oops !"},
+		{`map([1, 2], fn(x) { print(x) })`, "12", ""},
+		{`let f = fn() { puts("in closure") }; f()`, "\"in closure\"\n", ""},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		ctx := object.NewContext(nil, &stdout, &stderr)
+		evaluated := testEvalWithContext(tt.input, ctx)
+		if isError(evaluated) {
+			t.Errorf("unexpected error for %q: %s", tt.input, evaluated.Inspect())
+			continue
+		}
+
+		if stdout.String() != tt.stdout {
+			t.Errorf("wrong stdout for %q. want=%q, got=%q", tt.input, tt.stdout, stdout.String())
+		}
+		if stderr.String() != tt.stderr {
+			t.Errorf("wrong stderr for %q. want=%q, got=%q", tt.input, tt.stderr, stderr.String())
+		}
+	}
+}
+
+func TestInputBuiltins(t *testing.T) {
+	var stdout bytes.Buffer
+	ctx := object.NewContext(strings.NewReader("Ada\r\nLovelace\nlast"), &stdout, nil)
+
+	input := `[input("name? "), readline(), readline(), readline()]`
+	evaluated := testEvalWithContext(input, ctx)
+	if evaluated.Inspect() != `["Ada", "Lovelace", "last", null]` {
+		t.Errorf("wrong lines read, got=%s", evaluated.Inspect())
+	}
+
+	if stdout.String() != "name? " {
+		t.Errorf("prompt not written to stdout, got=%q", stdout.String())
+	}
+
+	evaluated = testEvalWithContext(`input(1)`, ctx)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok || errObj.Message != "argument to `input` not supported, want STRING, got INTEGER" {
+		t.Errorf("wrong result for input(1), got=%s", evaluated.Inspect())
+	}
+}
+
+func TestFileBuiltins(t *testing.T) {
+	ctx := object.NewContext(nil, nil, nil)
+	ctx.FS = object.NewMemFS(map[string]string{
+		"data/a.txt":     "alpha",
+		"data/sub/b.txt": "beta",
+		"readme":         "hi",
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`read_file("data/a.txt")`, `"alpha"`},
+		{`list_dir()`, `["data", "readme"]`},
+		{`list_dir("data")`, `["a.txt", "sub"]`},
+		{`[exists("data"), exists("data/sub/b.txt"), exists("nope")]`, "[true, true, false]"},
+		{`write_file("out/c.txt", "gamma"); read_file("./out/../out/c.txt")`, `"gamma"`},
+		{`remove("readme"); exists("readme")`, "false"},
+		{`read_file("../etc/passwd")`, "open ../etc/passwd: path escapes sandbox"},
+		{`write_file("/tmp/x", "")`, "open /tmp/x: path escapes sandbox"},
+		{`list_dir("data/../..")`, "open data/../..: path escapes sandbox"},
+		{`read_file("missing")`, "open missing: file does not exist"},
+		{`remove("data")`, "remove data: directory not empty"},
+		{`read_file(1)`, "argument to `read_file` not supported, want STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalWithContext(tt.input, ctx)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = errObj.Message
+		}
+
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestFileBuiltinsInDirectory(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	os.WriteFile(filepath.Join(root, "in.txt"), []byte("inside"), 0644)
+	os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644)
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks not supported: %s", err)
+	}
+
+	fsys, err := object.NewDirFS(root)
+	if err != nil {
+		t.Fatalf("NewDirFS: %s", err)
+	}
+
+	ctx := object.NewContext(nil, nil, nil)
+	ctx.FS = fsys
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`read_file("in.txt")`, `"inside"`},
+		{`write_file("new.txt", "x"); list_dir()`, `["in.txt", "link", "new.txt"]`},
+		{`read_file("missing.txt")`, "open missing.txt: no such file or directory"},
+		{`read_file("link/secret.txt")`, "open link/secret.txt: path escapes sandbox"},
+		{`write_file("link/planted.txt", "x")`, "open link/planted.txt: path escapes sandbox"},
+		{`remove(".")`, "remove .: permission denied"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalWithContext(tt.input, ctx)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = errObj.Message
+		}
+
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, got)
+		}
+	}
+
+	ctx.FS = object.ReadOnly(fsys)
+	evaluated := testEvalWithContext(`[read_file("in.txt"), write_file("in.txt", "")]`, ctx)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok || errObj.Message != "open in.txt: file system is read-only" {
+		t.Errorf("read-only file system accepted a write, got=%s", evaluated.Inspect())
+	}
+
+	evaluated = testEval(`read_file("in.txt")`)
+	errObj, ok = evaluated.(*object.Error)
+	if !ok || errObj.Message != "`read_file` not available, no file system configured" {
+		t.Errorf("file access allowed without a file system, got=%s", evaluated.Inspect())
+	}
+}
+
+func TestImport(t *testing.T) {
+	files := map[string]string{
+		"lib/util.monkey": `
+			import "./helpers";
+			let greeting = "hello";
+			let greet = fn(name) { greeting + ", " + helpers.shout(name) };
+		`,
+		"lib/helpers.monkey": `let shout = fn(s) { upper(s) + "!" };`,
+		"vendor/strs.monkey": `let twice = fn(s) { s + s };`,
+		"cycle/a.monkey":     `import "b"; let a = 1;`,
+		"cycle/b.monkey":     `import "a"; let b = 2;`,
+		"broken.monkey":      `let x = ;`,
+		"failing.monkey":     `let x = nope;`,
+		"my-util.monkey":     `let x = 1;`,
+		"if.monkey":          `let x = 1;`,
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/util"; util.greet("bob")`, `"hello, BOB!"`},
+		{`import "lib/util"; util`, "<module util>"},
+		{`import "lib/util.monkey"; util.greeting`, `"hello"`},
+		{`import "lib/util"; let first = util; import "lib/util"; first == util`, "true"},
+		{`import "strs"; strs.twice("ab")`, `"abab"`},
+		{`import "lib/util"; helpers`, "identifier not found: helpers"},
+		{`import "lib/util"; util.missing`, "module util has no member missing"},
+		{`import "cycle/a"`, "import cycle: cycle/a.monkey -> cycle/b.monkey -> cycle/a.monkey"},
+		{`import "nowhere"`, `import "nowhere": module not found`},
+		{`import "./strs"`, `import "./strs": module not found`},
+		{`import "../outside"`, `import "../outside": stat ../outside.monkey: path escapes sandbox`},
+		{`import "broken"`, "broken.monkey: parser errors: no prefix parse function for ; found; expected next token to be ;, got EOF instead"},
+		{`import "failing"`, "identifier not found: nope"},
+		{`import "my-util"`, `import "my-util": module name "my-util" is not an identifier`},
+		{`import "if"`, `import "if": module name "if" is not an identifier`},
+		{`math.sqrt(16.0)`, "4.0"},
+		{`let x = 1; x.y`, "member access not supported: INTEGER.y"},
+	}
+
+	for _, tt := range tests {
+		ctx := object.NewContext(nil, nil, nil)
+		ctx.FS = object.NewMemFS(files)
+		ctx.SearchPath = []string{"vendor"}
+
+		evaluated := testEvalWithContext(tt.input, ctx)
+		got := evaluated.Inspect()
+		if errObj, ok := evaluated.(*object.Error); ok {
+			got = errObj.Message
+		}
+
+		if got != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, got)
+		}
+	}
+
+	evaluated := testEval(`import "lib/util"`)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok || errObj.Message != `import "lib/util": no file system configured` {
+		t.Errorf("import allowed without a file system, got=%s", evaluated.Inspect())
+	}
+}
+
+func TestMemberAccess(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let config = {"db": {"host": "localhost", "port": 5432}}; config.db.host`, `"localhost"`},
+		{`let config = {"db": {"port": 5432}}; config.db.port + 1`, "5433"},
+		{`let h = {"f": fn(x) { x * 2 }}; h.f(21)`, "42"},
+		{`let h = {}; h?.missing`, "null"},
+		{`let h = {"a": {}}; h?.a?.b?.c`, "null"},
+		{`let n = if (false) { 1 }; n?.anything`, "null"},
+		{`math?.nope`, "null"},
+		{`{"a": 1}?.b.c`, "null"},
+		{`{"a": 1}?.b.c.d(1)[0]`, "null"},
+		{`let n = if (false) { 1 }; n?.a.b[1:2]`, "null"},
+		{`let calls = 0; let f = fn() { calls += 1; 0 }; {"a": 1}?.b[f()]; calls`, "0"},
+		{`{"a": {"b": 2}}?.a.b`, "2"},
+		{`let h = {"a": 1}; h.b = 2; h.a += 10; h`, `{"a":11, "b":2}`},
+		{`let h = {"db": {}}; h.db.host = "x"; h.db`, `{"host":"x"}`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMemberAccessErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let h = {"a": 1}; h.b`, "hash has no member b"},
+		{`let h = {"a": {}}; h?.a.b`, "hash has no member b"},
+		{`let n = if (false) { 1 }; n.x`, "member access not supported: NULL.x"},
+		{`let h = {1: 2}; h.a += 1`, "hash has no member a"},
+		{`let s = "abc"; s.x = 1`, "member assignment not supported: STRING.x"},
+		{`math.PI = 3`, "member assignment not supported: MODULE.PI"},
+		{`math["PI"] = 3`, "index assignment not supported: MODULE"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestMethodCalls(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"abc".upper()`, `"ABC"`},
+		{`"a,b,c".split(",").len()`, "3"},
+		{`"  hi ".trim().pad_left(4, "*")`, `"**hi"`},
+		{`"%s=%d".format("x", 1)`, `"x=1"`},
+		{`[1, 2, 3].push(4)`, "[1, 2, 3, 4]"},
+		{`[3, 1, 2].sort().map(fn(x) { x * 10 }).join`, "builtin function"},
+		{`[3, 1, 2].sort().map(fn(x) { x * 10 })`, "[10, 20, 30]"},
+		{`["a", "b"].join("-")`, `"a-b"`},
+		{`(1..5).filter(fn(x) { x > 3 })`, "[4, 5]"},
+		{`(1, 2, 2).uniq()`, "[1, 2]"},
+		{`{"a": 1, "b": 2}.keys()`, `["a", "b"]`},
+		{`let h = {"a": 1}; h.has("a")`, "true"},
+		{`let h = {"keys": 1}; h.keys`, "1"},
+		{`let upper = "x".upper; upper()`, `"X"`},
+		{`regex.compile("\d+").find_all("a1b22")`, `["1", "22"]`},
+		{`time.duration("90s").seconds()`, "90.0"},
+		{`"abc"?.nope`, "null"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMethodCallErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"abc".nope()`, "member access not supported: STRING.nope"},
+		{`5.upper()`, "member access not supported: INTEGER.upper"},
+		{`"abc".repeat("x")`, "argument to `repeat` not supported, want INTEGER, got STRING"},
+		{`{}.nope()`, "hash has no member nope"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+type testPoint struct {
+	X, Y int64
+}
+
+func (p *testPoint) Type() object.ObjectType { return "POINT" }
+func (p *testPoint) Inspect() string         { return "point" }
+
+func TestRegisterMethod(t *testing.T) {
+	RegisterMethod("POINT", "sum", func(ctx *object.Context, args ...object.Object) object.Object {
+		p := args[0].(*testPoint)
+		total := p.X + p.Y
+		for _, arg := range args[1:] {
+			total += arg.(*object.Integer).Value
+		}
+		return &object.Integer{Value: total}
+	})
+
+	l := lexer.New("p.sum(10)")
+	program := parser.New(l).ParseProgram()
+	env := object.NewEnvironment()
+	env.Set("p", &testPoint{X: 1, Y: 2})
+
+	evaluated := Eval(program, env)
+	testIntegerObject(t, evaluated, 13)
+}
+
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x, y }; Point(1, 2)`, "Point{x: 1, y: 2}"},
+		{`struct Point { x, y }; Point`, "<struct Point>"},
+		{`struct Point { x, y }; let p = Point(1, 2); p.x + p.y`, "3"},
+		{`struct Point { x, y }; let p = Point(1, 2); p.x = 10; p.y += 5; p`, "Point{x: 10, y: 7}"},
+		{`struct Point { x, y }; Point(1, [2]) == Point(1, [2])`, "true"},
+		{`struct Point { x, y }; Point(1, 2) != Point(2, 1)`, "true"},
+		{`struct A { v }; struct B { v }; A(1) == B(1)`, "false"},
+		{`struct User { name }; map(["a", "b"], User)`, `[User{name: "a"}, User{name: "b"}]`},
+		{`struct Empty {}; Empty()`, "Empty{}"},
+		{`struct Point { x, y }; Point(1, 2)?.z`, "null"},
+		{`struct Point { x, y }; let p = Point(1, 2); p.x = p; p`, "Point{x: Point{...}, y: 2}"},
+		{`struct Node { next }; let a = Node(0); let b = Node(a); a.next = b; a`, "Node{next: Node{next: Node{...}}}"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x, y }; Point(1)`, "wrong number of arguments, got=1, want=2"},
+		{`struct Point { x, y }; Point(1, 2).z`, "struct Point has no field z"},
+		{`struct Point { x, y }; let p = Point(1, 2); p.z = 3`, "struct Point has no field z"},
+		{`struct Point { x, y }; let p = Point(1, 2); p.z += 3`, "struct Point has no field z"},
+		{`struct Point { x, y }; Point(1, 2) + 1`, "type mismatch: INSTANCE + INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestClasses(t *testing.T) {
+	classes := `
+	class Counter {
+		init(start) { self.count = start }
+		inc() { self.count += 1; self }
+		get() { self.count }
+	}
+	class Animal {
+		init(name) { self.name = name }
+		speak() { self.name + " makes a sound" }
+		describe() { "I am " + self.name }
+	}
+	class Dog extends Animal {
+		init(name, breed) { super.init(name); self.breed = breed }
+		speak() { self.name + " barks" }
+		parent_speak() { super.speak() }
+	}
+	class Puppy extends Dog {
+		speak() { super.speak() + " softly" }
+	}
+	class Empty {}
+	class Node {
+		init(name) { self.name = name }
+		add(child) { child.parent = self; self.child = child; self }
+	}
+	`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Counter(5).inc().inc().get()", "7"},
+		{"let c = Counter(0); c.inc(); c.count", "1"},
+		{"Counter", "<class Counter>"},
+		{`Dog("Rex", "lab")`, `Dog{name: "Rex", breed: "lab"}`},
+		{`Dog("Rex", "lab").speak()`, `"Rex barks"`},
+		{`Dog("Rex", "lab").describe()`, `"I am Rex"`},
+		{`Dog("Rex", "lab").parent_speak()`, `"Rex makes a sound"`},
+		{`Puppy("Bit", "pug").speak()`, `"Bit barks softly"`},
+		{`let speak = Dog("Rex", "lab").speak; speak()`, `"Rex barks"`},
+		{`map(["a", "b"], fn(n) { Animal(n).speak() })`, `["a makes a sound", "b makes a sound"]`},
+		{`let d = Dog("Rex", "lab"); d.name = "Max"; d.speak()`, `"Max barks"`},
+		{`let a = Counter(1); let b = Counter(1); [a == a, a == b]`, "[true, false]"},
+		{"Empty()", "Empty{}"},
+		{"Empty()?.missing", "null"},
+		{`Node("a").add(Node("b"))`, `Node{name: "a", child: Node{name: "b", parent: Node{...}}}`},
+		{`let a = Node("a"); a.self = a; [a, a]`, `[Node{name: "a", self: Node{...}}, Node{name: "a", self: Node{...}}]`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(classes + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestClassErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"class A {}; A().x", "A object has no member x"},
+		{"class A {}; A(1)", "wrong number of arguments, got=1, want=0"},
+		{"class A { init(x) {} }; A()", "wrong number of arguments, got=0, want=1"},
+		{"class A { init() { nope } }; A()", "identifier not found: nope"},
+		{"class A { f() { super.f() } }; A().f()", "identifier not found: super"},
+		{"class A {}; class B extends A { f() { super.g() } }; B().f()", "superclass A has no method g"},
+		{"let A = 1; class B extends A {}", "superclass must be a class, got INTEGER"},
+		{"class B extends Missing {}", "identifier not found: Missing"},
+		{"class A { f() { self } }; let f = A.f;", "member access not supported: CLASS.f"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestEnums(t *testing.T) {
+	enums := "enum Color { Red, Green, Rgb(r, g, b) };"
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Color", "<enum Color>"},
+		{"Color.Red", "Color.Red"},
+		{"Color.Rgb", "<variant Color.Rgb>"},
+		{"Color.Rgb(1, 2, 3)", "Color.Rgb(1, 2, 3)"},
+		{"Color.Rgb(1, 2, 3).g", "2"},
+		{"[Color.Red == Color.Red, Color.Red == Color.Green]", "[true, false]"},
+		{"Color.Rgb(1, 2, 3) == Color.Rgb(1, 2, 3)", "true"},
+		{"Color.Rgb(1, 2, 3) == Color.Rgb(1, 2, 4)", "false"},
+		{"map([1, 2], fn(x) { Color.Rgb(x, x, x) })", "[Color.Rgb(1, 1, 1), Color.Rgb(2, 2, 2)]"},
+		{"Color?.Blue", "null"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(enums + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	decls := `
+	enum Shape { Circle(r), Rect(w, h), Empty };
+	struct Point { x, y };
+	let area = fn(s) {
+		match (s) {
+			Shape.Circle(r) => 3 * r * r,
+			Shape.Rect(w, h) if w == h => "square",
+			Shape.Rect(w, h) => w * h,
+			Shape.Empty => 0,
+		}
+	};
+	let describe = fn(x) {
+		match (x) {
+			0 => "zero",
+			-1 => "minus one",
+			1.5 => "one and a half",
+			"hi" => "greeting",
+			true => "yes",
+			[] => "empty",
+			[only] => "one: " + only,
+			[first, _, ...rest] => [first, rest],
+			{kind: "dog", name} => "dog " + name,
+			{"x": 0, y: y} => ["on the y axis at", y],
+			_ => "other",
+		}
+	};
+	`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"area(Shape.Circle(2))", "12"},
+		{"area(Shape.Rect(2, 2))", `"square"`},
+		{"area(Shape.Rect(2, 3))", "6"},
+		{"area(Shape.Empty)", "0"},
+		{"describe(0)", `"zero"`},
+		{"describe(-1)", `"minus one"`},
+		{"describe(1.5)", `"one and a half"`},
+		{`describe("hi")`, `"greeting"`},
+		{"describe(true)", `"yes"`},
+		{"describe([])", `"empty"`},
+		{`describe(["a"])`, `"one: a"`},
+		{`describe(["a", "b"])`, `["a", []]`},
+		{`describe(("a", "b", "c", "d"))`, `["a", ["c", "d"]]`},
+		{`describe({"kind": "dog", "name": "Rex", "age": 3})`, `"dog Rex"`},
+		{`describe({"kind": "cat", "name": "Tom"})`, `"other"`},
+		{`describe(Point(0, 7))`, `["on the y axis at", 7]`},
+		{"describe(101)", `"other"`},
+		{"match (101) { n if n > 100 => \"big\", _ => \"small\" }", `"big"`},
+		{"describe(false)", `"other"`},
+		{"match ([1, [2, 3]]) { [a, [b, c]] => a + b + c }", "6"},
+		{"match (Shape.Rect(1, 2)) { Shape.Rect => \"rect\" }", `"rect"`},
+		{"match (5) { x => { let y = x * 2; y + 1 } }", "11"},
+		{"let x = 1; match (2) { x => x }; x", "1"},
+		{"let f = fn(x) { match (x) { 1 => { return \"early\" } _ => 0 }; \"late\" }; [f(1), f(2)]", `["early", "late"]`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(decls + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (3) { 1 => 1, 2 => 2 }", "no match arm for 3"},
+		{`match ("x") { n if n > 1 => n }`, "type mismatch: STRING > INTEGER"},
+		{"enum E { A }; E.B", "enum E has no variant B"},
+		{"enum E { A(x) }; E.A(1).y", "variant E.A has no field y"},
+		{"enum E { A(x) }; E.A(1, 2)", "wrong number of arguments, got=2, want=1"},
+		{"enum E { A(x) }; match (1) { E.A(a, b) => 1 }", "pattern E.A(a, b): variant has 1 fields, pattern has 2"},
+		{"enum E { A }; match (1) { E.B => 1 }", "enum E has no variant B"},
+		{"let E = 1; match (1) { E.A => 1 }", "pattern E.A: E is not an enum, got INTEGER"},
+		{"match (1) { Nope.A => 1 }", "identifier not found: Nope"},
+		{"match (nope) { _ => 1 }", "identifier not found: nope"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestDestructuringLet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = [1, 2]; a + b", "3"},
+		{"let [a, b, ...rest] = [1, 2, 3, 4]; rest", "[3, 4]"},
+		{"let [a, ...rest] = [1]; rest", "[]"},
+		{"let [x, y] = (1, 2); [y, x]", "[2, 1]"},
+		{"let [_, [b, c]] = [1, [2, 3]]; b * c", "6"},
+		{`let {name, age: years} = {"name": "Ann", "age": 30, "city": "Oslo"}; [name, years]`, `["Ann", 30]`},
+		{`let {"full name": n} = {"full name": "Ann Lee"}; n`, `"Ann Lee"`},
+		{`let {tags: [first, ...others]} = {"tags": ["a", "b", "c"]}; [first, others]`, `["a", ["b", "c"]]`},
+		{"struct Point { x, y }; let {x, y} = Point(3, 4); x * y", "12"},
+		{"let [1, a] = [1, 2]; a", "2"},
+		{"let f = fn(pair) { let [a, b] = pair; a - b }; f([5, 3])", "2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDestructuringLetErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = 5;", "cannot destructure [a, b]: want ARRAY or TUPLE, got INTEGER"},
+		{"let [a, b] = [1, 2, 3];", "cannot destructure [a, b]: want 2 elements, got 3"},
+		{"let [a, b, ...c] = [1];", "cannot destructure [a, b, ...c]: want at least 2 elements, got 1"},
+		{"let [a, [b, c]] = [1, [2]];", "cannot destructure [b, c]: want 2 elements, got 1"},
+		{`let {name} = "Ann";`, "cannot destructure {name: name}: want HASH, got STRING"},
+		{`let {name, age} = {"name": "Ann"};`, `cannot destructure {name: name, age: age}: missing key age in {"name":"Ann"}`},
+		{"let [1, a] = [2, 3];", "cannot destructure 1: does not match 2"},
+		{"let [a, b] = nope;", "identifier not found: nope"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { 1 } catch (e) { 2 }", "1"},
+		{`try { throw "bad"; 1 } catch (e) { e.message }`, `"bad"`},
+		{`try { throw "bad" } catch (e) { [e.kind, e.value] }`, `["Error", "bad"]`},
+		{`try { throw {"code": 42} } catch (e) { e.value.code }`, "42"},
+		{`try { throw "bad" } catch (e) { e }`, "Error: bad"},
+		{`try { 1 + "a" } catch (e) { [e.kind, e.message] }`, `["TypeError", "type mismatch: INTEGER + STRING"]`},
+		{`try { len(1, 2) } catch (e) { e.kind }`, `"ArgumentError"`},
+		{`try { nope } catch (e) { e.kind }`, `"NameError"`},
+		{`try { 1 / 0 } catch (e) { e.kind }`, `"ZeroDivisionError"`},
+		{`try { let a = [1]; a[5] = 2 } catch (e) { e.kind }`, `"IndexError"`},
+		{`try { {"a": 1}.b } catch (e) { e.kind }`, `"AttributeError"`},
+		{`try { json_decode("{") } catch (e) { e.kind }`, `"ValueError"`},
+		{`try { match (1) { 2 => 2 } } catch (e) { e.kind }`, `"MatchError"`},
+		{`try { import "lib" } catch (e) { e.kind }`, `"ImportError"`},
+		{`try { math.nope } catch (e) { e.kind }`, `"AttributeError"`},
+		{`try { break_me() } catch (e) { e.kind }`, `"NameError"`},
+		{`try { throw error("negative", "ValueError") } catch (e) { [e.kind, e.message, e.value] }`, `["ValueError", "negative", null]`},
+		{"try { 1 / 0 } catch { -1 }", "-1"},
+		{"let f = fn(x) { 10 / x }; let g = fn(x) { f(x) + 1 }; try { g(0) } catch (e) { e.stack }", `["f", "g"]`},
+		{"let f = fn() { len(1) }; try { f() } catch (e) { e.stack }", `["len", "f"]`},
+		{`try { try { throw "inner" } catch (e) { throw e } } catch (e) { e.message }`, `"inner"`},
+		{`try { try { 1 + true } catch (e) { throw e } } catch (e) { e.kind }`, `"TypeError"`},
+		{`match (try { 1 / 0 } catch (e) { e }) { {kind: "ZeroDivisionError"} => "caught" }`, `"caught"`},
+		{"try { 1 / 0 } catch (e) { 0 }; e", "ERROR: identifier not found: e"},
+		{"let log = []; try { log = push(log, 1) } finally { log = push(log, 2) }; log", "[1, 2]"},
+		{"let log = []; try { 1 / 0 } catch (e) { log = push(log, 1) } finally { log = push(log, 2) }; log", "[1, 2]"},
+		{"try { 1 } finally { 2 }", "1"},
+		{"let f = fn() { try { return 1 } finally { 2 } }; f()", "1"},
+		{"let f = fn() { try { return 1 } finally { return 2 } }; f()", "2"},
+		{"let r = []; for (i in 1..4) { try { if (i == 2) { continue } r = push(r, i) } finally { } }; r", "[1, 3, 4]"},
+		{"let log = []; try { try { 1 / 0 } finally { log = push(log, 1) } } catch (e) { log = push(log, e.kind) }; log", `[1, "ZeroDivisionError"]`},
+		{`try { 1 } finally { throw "from finally" }`, "ERROR: from finally"},
+		{`try { throw "a" } catch (e) { throw "b" }`, "ERROR: b"},
+		{`throw 42`, "ERROR: 42"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestResults(t *testing.T) {
+	decls := `
+	let parse = fn(s) {
+		match (s) {
+			"one" => ok(1),
+			"two" => ok(2),
+			_ => err("cannot parse " + s),
+		}
+	};
+	let sum = fn(a, b) { ok(parse(a)? + parse(b)?) };
+	let log = [];
+	let traced = fn(s) { let v = parse(s)?; log = push(log, v); ok(v) };
+	`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ok(1)", "ok(1)"},
+		{`err("bad")`, `err("bad")`},
+		{"[ok(1) == ok(1), ok(1) == err(1), err(1) == err(1)]", "[true, false, true]"},
+		{`[is_ok(ok(1)), is_err(ok(1)), is_ok(err("x")), is_err(err("x"))]`, "[true, false, false, true]"},
+		{`[unwrap(ok(1)), unwrap_err(err("x")), unwrap_or(err("x"), 0), unwrap_or(ok(1), 0)]`, `[1, "x", 0, 1]`},
+		{`[ok(1).is_ok(), err("x").unwrap_or(5), err("x").unwrap_err()]`, `[true, 5, "x"]`},
+		{`sum("one", "two")`, "ok(3)"},
+		{`sum("one", "three")`, `err("cannot parse three")`},
+		{`sum("four", "one")`, `err("cannot parse four")`},
+		{`traced("three"); log`, "[]"},
+		{`traced("two"); log`, "[2]"},
+		{`map(["one", "x"], fn(s) { ok(parse(s)? * 10) })`, `[ok(10), err("cannot parse x")]`},
+		{`let f = fn() { for (s in ["one", "no", "two"]) { parse(s)?; } ok(true) }; f()`, `err("cannot parse no")`},
+		{`let f = fn() { if (parse("no")? > 0) { 1 } }; f()`, `err("cannot parse no")`},
+		{`let f = fn() { [parse("one")?, parse("two")?] }; f()`, "[1, 2]"},
+		{`parse("one")?`, "1"},
+		{`parse("x")?; 99`, `err("cannot parse x")`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(decls + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestResultErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1?", "unknown operator: INTEGER?"},
+		{`unwrap(err("bad"))`, `unwrap: called on err("bad")`},
+		{"unwrap_err(ok(1))", "unwrap_err: called on ok(1)"},
+		{"unwrap(1)", "argument to `unwrap` not supported, want RESULT, got INTEGER"},
+		{"ok()", "wrong number of arguments, got=0, want=1"},
+		{"unwrap_or(ok(1))", "wrong number of arguments, got=1, want=2"},
+		{"nope?", "identifier not found: nope"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"quote(5)", "5"},
+		{"quote(5 + 8)", "(5 + 8)"},
+		{"quote(foobar)", "foobar"},
+		{"quote(foobar + barfoo)", "(foobar + barfoo)"},
+		{"quote(unquote(4))", "4"},
+		{"quote(unquote(4 + 4))", "8"},
+		{"quote(8 + unquote(4 + 4))", "(8 + 8)"},
+		{"quote(unquote(4 + 4) + 8)", "(8 + 8)"},
+		{"let foobar = 8; quote(foobar)", "foobar"},
+		{"let foobar = 8; quote(unquote(foobar))", "8"},
+		{"quote(unquote(true))", "true"},
+		{"quote(unquote(true == false))", "false"},
+		{"quote(unquote(1.5))", "1.5"},
+		{`quote(unquote("a" + "b"))`, "ab"},
+		{"quote(unquote([1, 2 + 3]))", "[1, 5]"},
+		{"quote(unquote(quote(4 + 4)))", "(4 + 4)"},
+		{"let quotedInfixExpression = quote(4 + 4); quote(unquote(4 + 4) + unquote(quotedInfixExpression))", "(8 + (4 + 4))"},
+		{"quote(f(unquote(1 + 1), [unquote(2 + 2)]))", "f(2, [4])"},
+		{"let q = fn(x) { quote(unquote(x) + 1) }; q(1); q(5)", "(5 + 1)"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			t.Fatalf("expected *object.Quote for %q. got=%T (%+v)", tt.input, evaluated, evaluated)
+		}
+
+		if quote.Node == nil {
+			t.Fatalf("quote.Node is nil")
+		}
+
+		if quote.Node.String() != tt.expected {
+			t.Errorf("not equal. got=%q, want=%q", quote.Node.String(), tt.expected)
+		}
+	}
+}
+
+func TestQuoteUnquoteErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"quote()", "wrong number of arguments, got=0, want=1"},
+		{"quote(1, 2)", "wrong number of arguments, got=2, want=1"},
+		{"quote(unquote(1, 2))", "wrong number of arguments, got=2, want=1"},
+		{"quote(unquote(nope))", "identifier not found: nope"},
+		{`quote(unquote({"a": 1}))`, "unquote: unsupported value HASH"},
+		{"unquote(1)", "identifier not found: unquote"},
+		{"let m = fn() { macro(x) { x } }; m()", "macro must be defined with a top-level let"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q, got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func testParseProgram(input string) *ast.Program {
+	l := lexer.New(input)
+	p := parser.New(l)
+	return p.ParseProgram()
+}
+
+func TestDefineMacros(t *testing.T) {
+	input := `
+	let number = 1;
+	let function = fn(x, y) { x + y };
+	let mymacro = macro(x, y) { x + y; };
+	`
+
+	env := object.NewEnvironment()
+	program := testParseProgram(input)
+
+	DefineMacros(program, env)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("Wrong number of statements. got=%d", len(program.Statements))
+	}
+
+	if _, ok := env.Get("number"); ok {
+		t.Fatalf("number should not be defined")
+	}
+	if _, ok := env.Get("function"); ok {
+		t.Fatalf("function should not be defined")
+	}
+
+	obj, ok := env.Get("mymacro")
+	if !ok {
+		t.Fatalf("macro not in environment.")
+	}
+
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		t.Fatalf("object is not Macro. got=%T (%+v)", obj, obj)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("Wrong number of macro parameters. got=%d", len(macro.Parameters))
+	}
+
+	if macro.Parameters[0].String() != "x" || macro.Parameters[1].String() != "y" {
+		t.Fatalf("parameters are not 'x' and 'y'. got=%v", macro.Parameters)
+	}
+
+	expectedBody := "(x + y)"
+	if macro.Body.String() != expectedBody {
+		t.Fatalf("body is not %q. got=%q", expectedBody, macro.Body.String())
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let infixExpression = macro() { quote(1 + 2); }; infixExpression();`,
+			`(1 + 2)`,
+		},
+		{
+			`let reverse = macro(a, b) { quote(unquote(b) - unquote(a)); }; reverse(2 + 2, 10 - 5);`,
+			`(10 - 5) - (2 + 2)`,
+		},
+		{
+			`let unless = macro(condition, consequence, alternative) {
+				quote(if (!(unquote(condition))) {
+					unquote(consequence);
+				} else {
+					unquote(alternative);
+				});
+			};
+			unless(10 > 5, puts("not greater"), puts("greater"));`,
+			`if (!(10 > 5)) { puts("not greater") } else { puts("greater") }`,
+		},
+		{
+			`let twice = macro(x) { quote(unquote(x) + unquote(x)); }; let f = fn() { twice(twice(1)) };`,
+			`let f = fn() { ((1 + 1) + (1 + 1)) };`,
+		},
+	}
+
+	for _, tt := range tests {
+		expected := testParseProgram(tt.expected)
+		program := testParseProgram(tt.input)
+
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		expanded, err := ExpandMacros(program, env)
+		if err != nil {
+			t.Fatalf("ExpandMacros failed for %q: %s", tt.input, err.Message)
+		}
+
+		if expanded.String() != expected.String() {
+			t.Errorf("not equal. want=%q, got=%q", expected.String(), expanded.String())
+		}
+	}
+}
+
+func TestExpandMacrosErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let m = macro(x) { quote(x) }; m(1, 2)", "wrong number of arguments, got=2, want=1"},
+		{"let m = macro() { 1 }; m()", "macro m must return QUOTE, got INTEGER"},
+		{"let m = macro() { }; m()", "macro m must return QUOTE, got NULL"},
+		{"let m = macro() { nope }; m()", "identifier not found: nope"},
+	}
+
+	for _, tt := range tests {
+		program := testParseProgram(tt.input)
+
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		_, err := ExpandMacros(program, env)
+		if err == nil {
+			t.Errorf("no error for %q", tt.input)
+			continue
+		}
+
+		if err.Message != tt.expected {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.expected, err.Message)
+		}
+	}
+}
+
+func TestImportExpandsMacros(t *testing.T) {
+	ctx := object.NewContext(nil, nil, nil)
+	ctx.FS = object.NewMemFS(map[string]string{
+		"lib.monkey": `
+			let unless = macro(cond, then) { quote(if (!(unquote(cond))) { unquote(then) }) };
+			let check = fn(x) { unless(x > 0, "not positive") };
+		`,
+	})
+
+	evaluated := testEvalWithContext(`import "lib"; [lib.check(-1), lib.check(1)]`, ctx)
+	if evaluated.Inspect() != `["not positive", null]` {
+		t.Errorf("wrong result, got=%s", evaluated.Inspect())
+	}
+}
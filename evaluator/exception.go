@@ -0,0 +1,153 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func init() {
+	builtins["error"] = &object.Builtin{Fn: builtinError}
+}
+
+// The interpreter's own errors carry a kind chosen where they are created,
+// so scripts can tell them apart in catch without parsing messages. Errors
+// made with newError are of the plain kind "Error".
+
+func newKindError(kind, format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: kind}
+}
+
+func newNameError(format string, a ...interface{}) *object.Error {
+	return newKindError("NameError", format, a...)
+}
+
+func newArgumentError(format string, a ...interface{}) *object.Error {
+	return newKindError("ArgumentError", format, a...)
+}
+
+func newTypeError(format string, a ...interface{}) *object.Error {
+	return newKindError("TypeError", format, a...)
+}
+
+func newZeroDivisionError(format string, a ...interface{}) *object.Error {
+	return newKindError("ZeroDivisionError", format, a...)
+}
+
+func newIndexError(format string, a ...interface{}) *object.Error {
+	return newKindError("IndexError", format, a...)
+}
+
+func newAttributeError(format string, a ...interface{}) *object.Error {
+	return newKindError("AttributeError", format, a...)
+}
+
+func newMatchError(format string, a ...interface{}) *object.Error {
+	return newKindError("MatchError", format, a...)
+}
+
+func newImportError(format string, a ...interface{}) *object.Error {
+	return newKindError("ImportError", format, a...)
+}
+
+func newValueError(format string, a ...interface{}) *object.Error {
+	return newKindError("ValueError", format, a...)
+}
+
+func evalThrowStatement(node *ast.ThrowStatement, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isAbrupt(val) {
+		return val
+	}
+
+	// rethrowing a caught error keeps its kind and stack, but on a copy so
+	// the caught value does not change under whoever still holds it
+	if ex, ok := val.(*object.Exception); ok {
+		err := *ex.Error
+		err.Stack = append([]string(nil), ex.Error.Stack...)
+		return &err
+	}
+
+	msg := val.Inspect()
+	if s, ok := val.(*object.String); ok {
+		msg = s.Value
+	}
+
+	return &object.Error{Message: msg, Kind: "Error", Value: val}
+}
+
+func evalTryExpression(node *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && node.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		if node.Param != nil {
+			catchEnv.Set(node.Param.Value, &object.Exception{Error: err})
+		}
+
+		result = Eval(node.Catch, catchEnv)
+	}
+
+	// an error or a return, break or continue in finally replaces whatever
+	// the try or catch block ended with
+	if node.Finally != nil {
+		final := Eval(node.Finally, env)
+		if final != nil {
+			switch final.Type() {
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+				return final
+			}
+		}
+	}
+
+	return result
+}
+
+// exceptionField returns the message, kind, stack or thrown value of a
+// caught error.
+func exceptionField(ex *object.Exception, name string) (object.Object, bool) {
+	switch name {
+	case "message":
+		return &object.String{Value: ex.Error.Message}, true
+	case "kind":
+		return &object.String{Value: ex.Error.Kind}, true
+	case "stack":
+		frames := make([]object.Object, len(ex.Error.Stack))
+		for i, frame := range ex.Error.Stack {
+			frames[i] = &object.String{Value: frame}
+		}
+		return &object.Array{Elements: frames}, true
+	case "value":
+		if ex.Error.Value == nil {
+			return NULL, true
+		}
+		return ex.Error.Value, true
+	}
+
+	return nil, false
+}
+
+// builtinError creates an error value to throw, as in
+// throw error("negative amount", "ValueError").
+func builtinError(ctx *object.Context, args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newArgumentError("wrong number of arguments, got=%d, want=1 or 2", len(args))
+	}
+
+	msg, ok := args[0].(*object.String)
+	if !ok {
+		return newArgumentError("argument to `error` not supported, want STRING, got %s", args[0].Type())
+	}
+
+	kind := "Error"
+	if len(args) == 2 {
+		k, ok := args[1].(*object.String)
+		if !ok {
+			return newArgumentError("argument to `error` not supported, want STRING, got %s", args[1].Type())
+		}
+		kind = k.Value
+	}
+
+	return &object.Exception{Error: &object.Error{Message: msg.Value, Kind: kind}}
+}
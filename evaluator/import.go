@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"path"
+	"strings"
+
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/lexer"
+	"github.com/elsonwu/monkey-go/object"
+	"github.com/elsonwu/monkey-go/parser"
+	"github.com/elsonwu/monkey-go/token"
+)
+
+// ModuleExtension is added to import paths that do not name one.
+const ModuleExtension = ".monkey"
+
+// evalImportStatement binds the module loaded from the statement's path to
+// the path's base name, so import "lib/util" defines util.
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	ctx := env.Context()
+	if ctx.FS == nil {
+		return newImportError("import %q: no file system configured", node.Path)
+	}
+
+	file, err := resolveImport(ctx, env.File(), node.Path)
+	if err != nil {
+		return err
+	}
+
+	if name := moduleName(file); !isIdentifier(name) {
+		return newImportError("import %q: module name %q is not an identifier", node.Path, name)
+	}
+
+	module, err := loadModule(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	env.Set(module.Name, module)
+	return NULL
+}
+
+// resolveImport finds the file an import refers to. Paths starting with
+// ./ or ../ are relative to the importing file only; others are also looked
+// up in the context's search path.
+func resolveImport(ctx *object.Context, importer, name string) (string, *object.Error) {
+	file := name
+	if path.Ext(file) == "" {
+		file += ModuleExtension
+	}
+
+	dirs := []string{path.Dir(importer)}
+	if !strings.HasPrefix(name, "./") && !strings.HasPrefix(name, "../") {
+		dirs = append(dirs, ctx.SearchPath...)
+	}
+
+	for _, dir := range dirs {
+		candidate := path.Join(dir, file)
+		ok, err := ctx.FS.Exists(candidate)
+		if err != nil {
+			return "", newImportError("import %q: %s", name, err)
+		}
+
+		if ok {
+			return candidate, nil
+		}
+	}
+
+	return "", newImportError("import %q: module not found", name)
+}
+
+// loadModule evaluates file in its own environment the first time it is
+// imported and returns the cached module afterwards.
+func loadModule(ctx *object.Context, file string) (*object.Module, *object.Error) {
+	if module, ok := ctx.LoadedModule(file); ok {
+		return module, nil
+	}
+
+	if err := ctx.BeginImport(file); err != nil {
+		return nil, newImportError("%s", err)
+	}
+
+	module, err := evalModuleFile(ctx, file)
+	ctx.EndImport(file, module)
+
+	return module, err
+}
+
+func evalModuleFile(ctx *object.Context, file string) (*object.Module, *object.Error) {
+	source, readErr := ctx.FS.ReadFile(file)
+	if readErr != nil {
+		return nil, newError("%s", readErr)
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		return nil, newError("%s: parser errors: %s", file, strings.Join(p.Errors(), "; "))
+	}
+
+	macroEnv := object.NewEnvironmentWithContext(ctx)
+	DefineMacros(program, macroEnv)
+	expanded, expandErr := ExpandMacros(program, macroEnv)
+	if expandErr != nil {
+		return nil, expandErr
+	}
+
+	env := object.NewModuleEnvironment(ctx, file)
+	if result := Eval(expanded, env); isError(result) {
+		return nil, result.(*object.Error)
+	}
+
+	return &object.Module{Name: moduleName(file), Members: env.Bindings()}, nil
+}
+
+// moduleName is the name a module is bound to: its file's base name
+// without the extension.
+func moduleName(file string) string {
+	return strings.TrimSuffix(path.Base(file), path.Ext(file))
+}
+
+// isIdentifier reports whether name lexes as a single identifier, so that
+// scripts can refer to a module bound under it.
+func isIdentifier(name string) bool {
+	tok := lexer.New(name).NextToken()
+	return tok.Type == token.IDENT && tok.Literal == name
+}
@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/object"
+)
+
+// DefineMacros binds every top-level let of a macro literal in env and
+// removes those statements from the program.
+func DefineMacros(program *ast.Program, env *object.Environment) {
+	statements := program.Statements[:0]
+
+	for _, statement := range program.Statements {
+		if !isMacroDefinition(statement) {
+			statements = append(statements, statement)
+			continue
+		}
+
+		let := statement.(*ast.LetStatement)
+		macro := let.Value.(*ast.MacroLiteral)
+		env.Set(let.Name.Value, &object.Macro{
+			Parameters: macro.Parameters,
+			Body:       macro.Body,
+			Env:        env,
+		})
+	}
+
+	program.Statements = statements
+}
+
+func isMacroDefinition(node ast.Statement) bool {
+	let, ok := node.(*ast.LetStatement)
+	if !ok || let.Name == nil {
+		return false
+	}
+
+	_, ok = let.Value.(*ast.MacroLiteral)
+	return ok
+}
+
+// ExpandMacros replaces each call of a macro defined in env with the code
+// the macro returns. Arguments are expanded before the macro sees them, but
+// the code a macro returns is not expanded again.
+func ExpandMacros(program ast.Node, env *object.Environment) (ast.Node, *object.Error) {
+	var err *object.Error
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		if err != nil {
+			return node
+		}
+
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return node
+		}
+
+		macro, ok := isMacroCall(call, env)
+		if !ok {
+			return node
+		}
+
+		if len(call.Arguments) != len(macro.Parameters) {
+			err = newArgumentError("wrong number of arguments, got=%d, want=%d", len(call.Arguments), len(macro.Parameters))
+			return node
+		}
+
+		evalEnv := extendMacroEnv(macro, quoteArgs(call))
+		evaluated := unwrapReturnValue(Eval(macro.Body, evalEnv))
+
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			if err, ok = evaluated.(*object.Error); !ok {
+				err = newError("macro %s must return QUOTE, got %s", call.Function, typeOf(evaluated))
+			}
+			return node
+		}
+
+		return quote.Node
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expanded, nil
+}
+
+func isMacroCall(call *ast.CallExpression, env *object.Environment) (*object.Macro, bool) {
+	ident, ok := call.Function.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	obj, ok := env.Get(ident.Value)
+	if !ok {
+		return nil, false
+	}
+
+	macro, ok := obj.(*object.Macro)
+	return macro, ok
+}
+
+func quoteArgs(call *ast.CallExpression) []*object.Quote {
+	args := make([]*object.Quote, len(call.Arguments))
+	for i, a := range call.Arguments {
+		args[i] = &object.Quote{Node: a}
+	}
+
+	return args
+}
+
+func extendMacroEnv(macro *object.Macro, args []*object.Quote) *object.Environment {
+	extended := object.NewEnclosedEnvironment(macro.Env)
+	for i, param := range macro.Parameters {
+		extended.Set(param.Value, args[i])
+	}
+
+	return extended
+}
+
+func typeOf(obj object.Object) object.ObjectType {
+	if obj == nil {
+		return object.NULL_OBJ
+	}
+
+	return obj.Type()
+}
@@ -0,0 +1,238 @@
+package evaluator
+
+import (
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/object"
+)
+
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(node.Subject, env)
+	if isAbrupt(subject) {
+		return subject
+	}
+
+	for _, arm := range node.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+
+		matched, err := matchPattern(arm.Pattern, subject, armEnv)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isAbrupt(guard) {
+				return guard
+			}
+			if !isTruth(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, armEnv)
+	}
+
+	return newMatchError("no match arm for %s", subject.Inspect())
+}
+
+// matchPattern reports whether val has the shape of pattern, binding names
+// in env as it goes. Bindings made by a pattern that fails part way are
+// left behind, so callers give each attempt its own environment.
+func matchPattern(pattern ast.Pattern, val object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return true, nil
+
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, val)
+		return true, nil
+
+	case *ast.LiteralPattern:
+		literal := Eval(pattern.Value, env)
+		if err, ok := literal.(*object.Error); ok {
+			return false, err
+		}
+
+		return object.Equal(literal, val), nil
+
+	case *ast.ArrayPattern:
+		var elements []object.Object
+		switch val := val.(type) {
+		case *object.Array:
+			elements = val.Elements
+		case *object.Tuple:
+			elements = val.Elements
+		default:
+			return false, nil
+		}
+
+		if len(elements) < len(pattern.Elements) || pattern.Rest == nil && len(elements) != len(pattern.Elements) {
+			return false, nil
+		}
+
+		for i, p := range pattern.Elements {
+			if matched, err := matchPattern(p, elements[i], env); !matched || err != nil {
+				return false, err
+			}
+		}
+
+		if pattern.Rest != nil {
+			rest := make([]object.Object, len(elements)-len(pattern.Elements))
+			copy(rest, elements[len(pattern.Elements):])
+			return matchPattern(pattern.Rest, &object.Array{Elements: rest}, env)
+		}
+
+		return true, nil
+
+	case *ast.HashPattern:
+		for i, key := range pattern.Keys {
+			field, ok := fieldOf(val, key)
+			if !ok {
+				return false, nil
+			}
+
+			if matched, err := matchPattern(pattern.Values[i], field, env); !matched || err != nil {
+				return false, err
+			}
+		}
+
+		return true, nil
+
+	case *ast.VariantPattern:
+		return matchVariant(pattern, val, env)
+
+	default:
+		return false, newError("unknown pattern: %T", pattern)
+	}
+}
+
+func matchVariant(pattern *ast.VariantPattern, val object.Object, env *object.Environment) (bool, *object.Error) {
+	obj := evalIdentifier(pattern.Enum, env)
+	if err, ok := obj.(*object.Error); ok {
+		return false, err
+	}
+
+	enum, ok := obj.(*object.Enum)
+	if !ok {
+		return false, newError("pattern %s: %s is not an enum, got %s", pattern, pattern.Enum, obj.Type())
+	}
+
+	variant := enum.Variant(pattern.Variant.Value)
+	if variant == nil {
+		return false, newAttributeError("enum %s has no variant %s", enum.Name, pattern.Variant.Value)
+	}
+
+	if pattern.Args != nil && len(pattern.Args) != len(variant.Fields) {
+		return false, newError("pattern %s: variant has %d fields, pattern has %d", pattern, len(variant.Fields), len(pattern.Args))
+	}
+
+	ev, ok := val.(*object.EnumValue)
+	if !ok || ev.Variant != variant {
+		return false, nil
+	}
+
+	for i, p := range pattern.Args {
+		if matched, err := matchPattern(p, ev.Values[i], env); !matched || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// fieldOf looks up key as a hash's string key or as a named field of a
+// struct instance, class object, enum value or caught error.
+func fieldOf(obj object.Object, key string) (object.Object, bool) {
+	switch obj := obj.(type) {
+	case *object.Hash:
+		pair, ok := obj.Get((&object.String{Value: key}).HashKey())
+		return pair.Value, ok
+	case *object.Instance:
+		if idx := obj.Struct.FieldIndex(key); idx >= 0 {
+			return obj.Values[idx], true
+		}
+	case *object.ClassObject:
+		return obj.Get(key)
+	case *object.EnumValue:
+		if idx := obj.Variant.FieldIndex(key); idx >= 0 {
+			return obj.Values[idx], true
+		}
+	case *object.Exception:
+		return exceptionField(obj, key)
+	}
+
+	return nil, false
+}
+
+// destructure binds the parts of val named by pattern in env, as let does
+// with a pattern in place of a name. Unlike matchPattern, a value of the
+// wrong shape is an error, which names the part of the pattern that failed.
+func destructure(pattern ast.Pattern, val object.Object, env *object.Environment) *object.Error {
+	switch pattern := pattern.(type) {
+	case *ast.ArrayPattern:
+		var elements []object.Object
+		switch val := val.(type) {
+		case *object.Array:
+			elements = val.Elements
+		case *object.Tuple:
+			elements = val.Elements
+		default:
+			return newMatchError("cannot destructure %s: want ARRAY or TUPLE, got %s", pattern, val.Type())
+		}
+
+		if pattern.Rest == nil && len(elements) != len(pattern.Elements) {
+			return newMatchError("cannot destructure %s: want %d elements, got %d", pattern, len(pattern.Elements), len(elements))
+		}
+		if len(elements) < len(pattern.Elements) {
+			return newMatchError("cannot destructure %s: want at least %d elements, got %d", pattern, len(pattern.Elements), len(elements))
+		}
+
+		for i, p := range pattern.Elements {
+			if err := destructure(p, elements[i], env); err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			rest := make([]object.Object, len(elements)-len(pattern.Elements))
+			copy(rest, elements[len(pattern.Elements):])
+			return destructure(pattern.Rest, &object.Array{Elements: rest}, env)
+		}
+
+		return nil
+
+	case *ast.HashPattern:
+		switch val.(type) {
+		case *object.Hash, *object.Instance, *object.ClassObject, *object.EnumValue, *object.Exception:
+		default:
+			return newMatchError("cannot destructure %s: want HASH, got %s", pattern, val.Type())
+		}
+
+		for i, key := range pattern.Keys {
+			field, ok := fieldOf(val, key)
+			if !ok {
+				return newMatchError("cannot destructure %s: missing key %s in %s", pattern, key, val.Inspect())
+			}
+
+			if err := destructure(pattern.Values[i], field, env); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		matched, err := matchPattern(pattern, val, env)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			return newMatchError("cannot destructure %s: does not match %s", pattern, val.Inspect())
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package evaluator
+
+import (
+	"sync"
+
+	"github.com/elsonwu/monkey-go/object"
+)
+
+var (
+	methodsMu sync.RWMutex
+	methods   = map[object.ObjectType]map[string]object.BuiltinFunction{}
+)
+
+// RegisterMethod makes fn callable as value.name(args) on every value of
+// type t. The value is passed to fn ahead of the call's own arguments, so
+// most builtins can be registered as methods unchanged. Hosts use this to
+// give methods to their own object types as well.
+func RegisterMethod(t object.ObjectType, name string, fn object.BuiltinFunction) {
+	methodsMu.Lock()
+	defer methodsMu.Unlock()
+
+	if methods[t] == nil {
+		methods[t] = make(map[string]object.BuiltinFunction)
+	}
+	methods[t][name] = fn
+}
+
+// boundMethod returns the method name of obj's type bound to obj, or nil.
+func boundMethod(obj object.Object, name string) *object.Builtin {
+	methodsMu.RLock()
+	fn, ok := methods[obj.Type()][name]
+	methodsMu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return &object.Builtin{
+		Fn: func(ctx *object.Context, args ...object.Object) object.Object {
+			return fn(ctx, append([]object.Object{obj}, args...)...)
+		},
+	}
+}
+
+func registerMethods(t object.ObjectType, fns map[string]object.BuiltinFunction) {
+	for name, fn := range fns {
+		RegisterMethod(t, name, fn)
+	}
+}
+
+func init() {
+	sequence := map[string]object.BuiltinFunction{
+		"len":    builtins["len"].Fn,
+		"map":    builtinMap,
+		"filter": builtinFilter,
+		"reduce": builtinReduce,
+		"find":   builtinFind,
+		"any":    builtinAny,
+		"all":    builtinAll,
+		"sort":   builtinSort,
+		"zip":    builtinZip,
+		"uniq":   builtinUniq,
+	}
+
+	registerMethods(object.ARRAY_OBJ, sequence)
+	registerMethods(object.TUPLE_OBJ, sequence)
+	registerMethods(object.RANGE_OBJ, sequence)
+
+	registerMethods(object.ARRAY_OBJ, map[string]object.BuiltinFunction{
+		"first":   builtins["first"].Fn,
+		"last":    builtins["last"].Fn,
+		"rest":    builtins["rest"].Fn,
+		"push":    builtins["push"].Fn,
+		"flatten": builtinFlatten,
+		"join":    builtinJoin,
+	})
+
+	registerMethods(object.STRING_OBJ, map[string]object.BuiltinFunction{
+		"len":         builtins["len"].Fn,
+		"split":       builtinSplit,
+		"trim":        builtinTrim,
+		"upper":       builtinUpper,
+		"lower":       builtinLower,
+		"contains":    builtinContains,
+		"starts_with": builtinStartsWith,
+		"ends_with":   builtinEndsWith,
+		"replace":     builtinReplace,
+		"index_of":    builtinIndexOf,
+		"repeat":      builtinRepeat,
+		"pad_left":    builtinPadLeft,
+		"pad_right":   builtinPadRight,
+		"chars":       builtinChars,
+		"format":      builtinFormat,
+	})
+
+	registerMethods(object.HASH_OBJ, map[string]object.BuiltinFunction{
+		"keys":   builtinKeys,
+		"values": builtinValues,
+		"has":    builtinHas,
+		"delete": builtinDelete,
+		"merge":  builtinMerge,
+	})
+
+	registerMethods(object.REGEX_OBJ, map[string]object.BuiltinFunction{
+		"test":     regexTest,
+		"match":    regexMatch,
+		"groups":   regexGroups,
+		"find_all": regexFindAll,
+		"replace":  regexReplace,
+		"split":    regexSplit,
+	})
+
+	registerMethods(object.TIME_OBJ, map[string]object.BuiltinFunction{
+		"format": timeFormat,
+		"unix":   timeUnix,
+	})
+
+	registerMethods(object.DURATION_OBJ, map[string]object.BuiltinFunction{
+		"seconds": timeSeconds,
+	})
+
+	registerMethods(object.RESULT_OBJ, map[string]object.BuiltinFunction{
+		"is_ok":      builtinIsOk,
+		"is_err":     builtinIsErr,
+		"unwrap":     builtinUnwrap,
+		"unwrap_err": builtinUnwrapErr,
+		"unwrap_or":  builtinUnwrapOr,
+	})
+}
@@ -0,0 +1,104 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/elsonwu/monkey-go/ast"
+	"github.com/elsonwu/monkey-go/object"
+	"github.com/elsonwu/monkey-go/token"
+)
+
+// isCallTo reports whether node is a call of the bare name fn, as quote and
+// unquote are recognized by name rather than being evaluated.
+func isCallTo(node ast.Node, fn string) bool {
+	call, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == fn
+}
+
+// quote returns node with its unquote calls replaced by their values. It
+// works on a copy, as the same quote may run again with other values.
+func quote(node ast.Node, env *object.Environment) object.Object {
+	var err *object.Error
+
+	node = ast.Modify(ast.Copy(node), func(node ast.Node) ast.Node {
+		if err != nil || !isCallTo(node, "unquote") {
+			return node
+		}
+
+		call := node.(*ast.CallExpression)
+		if len(call.Arguments) != 1 {
+			err = newArgumentError("wrong number of arguments, got=%d, want=1", len(call.Arguments))
+			return node
+		}
+
+		unquoted := Eval(call.Arguments[0], env)
+		if isAbrupt(unquoted) {
+			err, _ = unquoted.(*object.Error)
+			if err == nil {
+				err = newError("unquote: return outside function")
+			}
+			return node
+		}
+
+		var converted ast.Node
+		converted, err = convertObjectToASTNode(unquoted)
+		if err != nil {
+			return node
+		}
+
+		return converted
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return &object.Quote{Node: node}
+}
+
+// convertObjectToASTNode turns an unquoted value back into code that
+// evaluates to it.
+func convertObjectToASTNode(obj object.Object) (ast.Node, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		t := token.Token{Type: token.INT, Literal: fmt.Sprintf("%d", obj.Value)}
+		return &ast.IntegerLiteral{Token: t, Value: obj.Value}, nil
+
+	case *object.Float:
+		t := token.Token{Type: token.FLOAT, Literal: obj.Inspect()}
+		return &ast.FloatLiteral{Token: t, Value: obj.Value}, nil
+
+	case *object.Boolean:
+		t := token.Token{Type: token.FALSE, Literal: "false"}
+		if obj.Value {
+			t = token.Token{Type: token.TRUE, Literal: "true"}
+		}
+		return &ast.Boolean{Token: t, Value: obj.Value}, nil
+
+	case *object.String:
+		t := token.Token{Type: token.STRING, Literal: obj.Value}
+		return &ast.StringLiteral{Token: t, Value: obj.Value}, nil
+
+	case *object.Array:
+		array := &ast.ArrayLiteral{Token: token.Token{Type: token.LBRACKET, Literal: "["}}
+		for _, e := range obj.Elements {
+			element, err := convertObjectToASTNode(e)
+			if err != nil {
+				return nil, err
+			}
+			array.Elements = append(array.Elements, element.(ast.Expression))
+		}
+		return array, nil
+
+	case *object.Quote:
+		return obj.Node, nil
+
+	default:
+		return nil, newError("unquote: unsupported value %s", obj.Type())
+	}
+}
@@ -32,6 +32,21 @@ if (5 < 10) {
 "bar foo"
 [1, 2];
 {"a":1};
+a[0] += 1;
+b -= 2;
+c *= 3;
+d /= 4;
+while for in break continue
+1..10 0..<n
+3.14 1.5..2
+import "lib/util"; util.name
+a?.b
+struct
+class Dog extends Animal
+enum match => [a, ...b] x == 1
+try catch finally throw
+f(x)?
+macro(x) { x }
 `
 	tests := []struct {
 		expectedType    token.TokenType
@@ -132,6 +147,87 @@ if (5 < 10) {
 		{token.RBRACE, "}"},
 		{token.SEMICOLON, ";"},
 
+		{token.IDENT, "a"},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.RBRACKET, "]"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "b"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "c"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "3"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "d"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "4"},
+		{token.SEMICOLON, ";"},
+
+		{token.WHILE, "while"},
+		{token.FOR, "for"},
+		{token.IN, "in"},
+		{token.BREAK, "break"},
+		{token.CONTINUE, "continue"},
+
+		{token.INT, "1"},
+		{token.RANGE, ".."},
+		{token.INT, "10"},
+		{token.INT, "0"},
+		{token.RANGE_EXCLUSIVE, "..<"},
+		{token.IDENT, "n"},
+
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "1.5"},
+		{token.RANGE, ".."},
+		{token.INT, "2"},
+
+		{token.IMPORT, "import"},
+		{token.STRING, "lib/util"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "util"},
+		{token.DOT, "."},
+		{token.IDENT, "name"},
+		{token.IDENT, "a"},
+		{token.OPTIONAL_DOT, "?."},
+		{token.IDENT, "b"},
+		{token.STRUCT, "struct"},
+		{token.CLASS, "class"},
+		{token.IDENT, "Dog"},
+		{token.EXTENDS, "extends"},
+		{token.IDENT, "Animal"},
+		{token.ENUM, "enum"},
+		{token.MATCH, "match"},
+		{token.ARROW, "=>"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "b"},
+		{token.RBRACKET, "]"},
+		{token.IDENT, "x"},
+		{token.EQ, "=="},
+		{token.INT, "1"},
+		{token.TRY, "try"},
+		{token.CATCH, "catch"},
+		{token.FINALLY, "finally"},
+		{token.THROW, "throw"},
+		{token.IDENT, "f"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.QUESTION, "?"},
+		{token.MACRO, "macro"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+
 		{token.EOF, ""},
 	}
 
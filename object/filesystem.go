@@ -0,0 +1,302 @@
+package object
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrPathEscape is reported for paths that would leave the sandbox.
+	ErrPathEscape = errors.New("path escapes sandbox")
+	// ErrReadOnly is reported for writes to a read-only file system.
+	ErrReadOnly = errors.New("file system is read-only")
+)
+
+// FileSystem is the file access a Context grants to scripts. Names are
+// slash-separated and relative to the file system's root.
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte) error
+	ReadDir(name string) ([]string, error)
+	Exists(name string) (bool, error)
+	Remove(name string) error
+}
+
+// cleanPath normalizes a script-supplied name, rejecting absolute paths and
+// any that climb above the root.
+func cleanPath(op, name string) (string, error) {
+	p := path.Clean(filepath.ToSlash(name))
+	if path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+		return "", &fs.PathError{Op: op, Path: name, Err: ErrPathEscape}
+	}
+
+	return p, nil
+}
+
+type dirFS struct {
+	root string
+}
+
+// NewDirFS confines scripts to the directory tree under root. Symbolic links
+// that lead outside of root are treated as escapes.
+func NewDirFS(root string) (FileSystem, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	real, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dirFS{root: real}, nil
+}
+
+// resolve maps name to a host path, following symbolic links in whatever
+// part of it already exists to make sure they stay under the root.
+func (d *dirFS) resolve(op, name string) (string, error) {
+	p, err := cleanPath(op, name)
+	if err != nil {
+		return "", err
+	}
+
+	full := filepath.Join(d.root, filepath.FromSlash(p))
+	for existing := full; ; existing = filepath.Dir(existing) {
+		real, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			rel, err := filepath.Rel(d.root, real)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return "", &fs.PathError{Op: op, Path: name, Err: ErrPathEscape}
+			}
+			break
+		}
+
+		if !os.IsNotExist(err) || existing == d.root {
+			break
+		}
+
+		// a dangling link could still create a file wherever it points
+		if _, err := os.Lstat(existing); err == nil {
+			return "", &fs.PathError{Op: op, Path: name, Err: ErrPathEscape}
+		}
+	}
+
+	return full, nil
+}
+
+// hostError replaces the host path in err with the script's name for it, so
+// errors do not reveal where the root lives.
+func hostError(err error, name string) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		return &fs.PathError{Op: pe.Op, Path: name, Err: pe.Err}
+	}
+
+	return err
+}
+
+func (d *dirFS) ReadFile(name string) ([]byte, error) {
+	full, err := d.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(full)
+	return data, hostError(err, name)
+}
+
+func (d *dirFS) WriteFile(name string, data []byte) error {
+	full, err := d.resolve("open", name)
+	if err != nil {
+		return err
+	}
+
+	return hostError(os.WriteFile(full, data, 0644), name)
+}
+
+func (d *dirFS) ReadDir(name string) ([]string, error) {
+	full, err := d.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(full)
+	if err != nil {
+		return nil, hostError(err, name)
+	}
+
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name()
+	}
+
+	return names, nil
+}
+
+func (d *dirFS) Exists(name string) (bool, error) {
+	full, err := d.resolve("stat", name)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(full)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return err == nil, hostError(err, name)
+}
+
+func (d *dirFS) Remove(name string) error {
+	full, err := d.resolve("remove", name)
+	if err != nil {
+		return err
+	}
+
+	if full == d.root {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrPermission}
+	}
+
+	return hostError(os.Remove(full), name)
+}
+
+// MemFS is an in-memory FileSystem for tests and embedding. Directories
+// exist implicitly whenever a file lives beneath them.
+type MemFS struct {
+	files map[string][]byte
+}
+
+// NewMemFS creates an in-memory file system holding the given files.
+func NewMemFS(files map[string]string) *MemFS {
+	m := &MemFS{files: make(map[string][]byte)}
+	for name, content := range files {
+		m.files[path.Clean(name)] = []byte(content)
+	}
+
+	return m
+}
+
+func (m *MemFS) isDir(p string) bool {
+	if p == "." {
+		return true
+	}
+
+	for name := range m.files {
+		if strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	p, err := cleanPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := m.files[p]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return append([]byte(nil), data...), nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte) error {
+	p, err := cleanPath("open", name)
+	if err != nil {
+		return err
+	}
+
+	if m.isDir(p) {
+		return &fs.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+	}
+
+	m.files[p] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemFS) ReadDir(name string) ([]string, error) {
+	p, err := cleanPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.isDir(p) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	prefix := p + "/"
+	if p == "." {
+		prefix = ""
+	}
+
+	seen := make(map[string]bool)
+	names := []string{}
+	for file := range m.files {
+		if !strings.HasPrefix(file, prefix) {
+			continue
+		}
+
+		child := strings.SplitN(strings.TrimPrefix(file, prefix), "/", 2)[0]
+		if !seen[child] {
+			seen[child] = true
+			names = append(names, child)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (m *MemFS) Exists(name string) (bool, error) {
+	p, err := cleanPath("stat", name)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := m.files[p]
+	return ok || m.isDir(p), nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	p, err := cleanPath("remove", name)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := m.files[p]; !ok {
+		if m.isDir(p) {
+			return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+		}
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+
+	delete(m.files, p)
+	return nil
+}
+
+type readOnlyFS struct {
+	FileSystem
+}
+
+// ReadOnly wraps a FileSystem so that scripts can read but not change it.
+func ReadOnly(fsys FileSystem) FileSystem {
+	return readOnlyFS{fsys}
+}
+
+func (r readOnlyFS) WriteFile(name string, data []byte) error {
+	return &fs.PathError{Op: "open", Path: name, Err: ErrReadOnly}
+}
+
+func (r readOnlyFS) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnly}
+}
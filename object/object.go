@@ -1,10 +1,17 @@
 package object
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
-	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/elsonwu/monkey-go/ast"
 )
@@ -14,12 +21,33 @@ const (
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NULL_OBJ         = "NULL"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
+	BREAK_OBJ        = "BREAK"
+	CONTINUE_OBJ     = "CONTINUE"
 	ERROR_OBJ        = "ERROR"
 	FUNCTION_OBJ     = "FUNCTION"
 	STRING_OBJ       = "STRING"
 	BUILTIN_OBJ      = "BULITIN"
 	ARRAY_OBJ        = "ARRAY"
 	HASH_OBJ         = "HASH"
+	RANGE_OBJ        = "RANGE"
+	TUPLE_OBJ        = "TUPLE"
+	FLOAT_OBJ        = "FLOAT"
+	MODULE_OBJ       = "MODULE"
+	REGEX_OBJ        = "REGEX"
+	TIME_OBJ         = "TIME"
+	DURATION_OBJ     = "DURATION"
+	STRUCT_OBJ       = "STRUCT"
+	INSTANCE_OBJ     = "INSTANCE"
+	CLASS_OBJ        = "CLASS"
+	OBJECT_OBJ       = "OBJECT"
+	SUPER_OBJ        = "SUPER"
+	ENUM_OBJ         = "ENUM"
+	VARIANT_OBJ      = "VARIANT"
+	ENUM_VALUE_OBJ   = "ENUM_VALUE"
+	EXCEPTION_OBJ    = "EXCEPTION"
+	RESULT_OBJ       = "RESULT"
+	QUOTE_OBJ        = "QUOTE"
+	MACRO_OBJ        = "MACRO"
 )
 
 type ObjectType string
@@ -29,6 +57,73 @@ type Object interface {
 	Inspect() string
 }
 
+// Comparable is implemented by objects that have value semantics for the
+// == and != operators.
+type Comparable interface {
+	Equal(other Object) bool
+}
+
+// container is implemented by Comparable objects that hold other objects.
+// Their equality threads the pairs already being compared, so a value that
+// contains itself compares without recursing forever.
+type container interface {
+	equal(other Object, seen map[objectPair]bool) bool
+}
+
+type objectPair struct {
+	a, b Object
+}
+
+// Equal reports whether a and b are equal: structurally when a is
+// Comparable, by identity otherwise.
+func Equal(a, b Object) bool {
+	return equal(a, b, nil)
+}
+
+func equal(a, b Object, seen map[objectPair]bool) bool {
+	switch c := a.(type) {
+	case container:
+		if a == b {
+			return true
+		}
+
+		// a pair met again while it is still being compared is part of a
+		// cycle; any difference shows up elsewhere
+		pair := objectPair{a, b}
+		if seen[pair] {
+			return true
+		}
+
+		if seen == nil {
+			seen = map[objectPair]bool{}
+		}
+		seen[pair] = true
+
+		return c.equal(b, seen)
+
+	case Comparable:
+		return c.Equal(b)
+	}
+
+	return a == b
+}
+
+// inspector is implemented by objects whose printed form includes other
+// objects. Their Inspect threads the containers already being printed, so a
+// value that contains itself prints the repeat as a placeholder, such as
+// [...] for an array.
+type inspector interface {
+	inspect(seen map[Object]bool) string
+}
+
+func inspect(obj Object, seen map[Object]bool) string {
+	if i, ok := obj.(inspector); ok {
+		return i.inspect(seen)
+	}
+
+	return obj.Inspect()
+}
+
 type ReturnValue struct {
 	Value Object
 }
@@ -40,8 +135,35 @@ func (rv *ReturnValue) Inspect() string {
 	return rv.Value.Inspect()
 }
 
+// Break and Continue are signals, like ReturnValue, that unwind block
+// evaluation up to the nearest enclosing loop.
+type Break struct{}
+
+func (b *Break) Type() ObjectType {
+	return BREAK_OBJ
+}
+func (b *Break) Inspect() string {
+	return "break"
+}
+
+type Continue struct{}
+
+func (c *Continue) Type() ObjectType {
+	return CONTINUE_OBJ
+}
+func (c *Continue) Inspect() string {
+	return "continue"
+}
+
+// Error is a raised error on its way up to the nearest catch. Kind names
+// the sort of error, such as TypeError, and Stack lists the calls it left,
+// innermost first. Value is whatever a script threw, nil for errors raised
+// by the interpreter itself.
 type Error struct {
 	Message string
+	Kind    string
+	Stack   []string
+	Value   Object
 }
 
 func (e *Error) Type() ObjectType {
@@ -51,6 +173,19 @@ func (e *Error) Inspect() string {
 	return "ERROR: " + e.Message
 }
 
+// Exception is a caught Error as a script sees it, an ordinary value that
+// can be inspected or thrown again.
+type Exception struct {
+	Error *Error
+}
+
+func (e *Exception) Type() ObjectType {
+	return EXCEPTION_OBJ
+}
+func (e *Exception) Inspect() string {
+	return e.Error.Kind + ": " + e.Error.Message
+}
+
 type Integer struct {
 	Value int64
 }
@@ -63,6 +198,18 @@ func (i *Integer) Inspect() string {
 	return fmt.Sprintf("%d", i.Value)
 }
 
+// Equal treats an integer and a float holding the same number as equal.
+func (i *Integer) Equal(other Object) bool {
+	switch o := other.(type) {
+	case *Integer:
+		return o.Value == i.Value
+	case *Float:
+		return o.Value == float64(i.Value)
+	}
+
+	return false
+}
+
 func (i *Integer) HashKey() HashKey {
 	return HashKey{
 		Type:  i.Type(),
@@ -70,6 +217,41 @@ func (i *Integer) HashKey() HashKey {
 	}
 }
 
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Type() ObjectType {
+	return FLOAT_OBJ
+}
+
+// Inspect always shows a decimal point or exponent so that floats remain
+// distinguishable from integers.
+func (f *Float) Inspect() string {
+	format := byte('f')
+	if abs := math.Abs(f.Value); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'g'
+	}
+
+	s := strconv.FormatFloat(f.Value, format, -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+
+	return s
+}
+
+func (f *Float) Equal(other Object) bool {
+	switch o := other.(type) {
+	case *Float:
+		return o.Value == f.Value
+	case *Integer:
+		return float64(o.Value) == f.Value
+	}
+
+	return false
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -82,6 +264,11 @@ func (i *Boolean) Inspect() string {
 	return fmt.Sprintf("%t", i.Value)
 }
 
+func (i *Boolean) Equal(other Object) bool {
+	o, ok := other.(*Boolean)
+	return ok && o.Value == i.Value
+}
+
 func (i *Boolean) HashKey() HashKey {
 	var h uint64
 	if i.Value {
@@ -106,9 +293,122 @@ func (i *Null) Inspect() string {
 	return "null"
 }
 
+func (i *Null) Equal(other Object) bool {
+	_, ok := other.(*Null)
+	return ok
+}
+
+// Context carries the host resources available to a running program. Every
+// builtin receives the context of the environment it was called from.
+type Context struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	Stdin  *bufio.Reader
+
+	// FS is the file system scripts may use; file builtins and import fail
+	// while it is nil.
+	FS FileSystem
+
+	// SearchPath lists directories of FS that import searches after the
+	// importing file's own directory.
+	SearchPath []string
+
+	// Clock is behind time.now and time.since, so hosts can pin the
+	// current time in tests and replays. A nil clock is the system clock.
+	Clock func() time.Time
+
+	modules   map[string]*Module
+	importing []string
+	random    *rand.Rand
+}
+
+// Now returns the current time from the context's clock.
+func (c *Context) Now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+
+	return c.Clock()
+}
+
+// DefaultRandomSeed seeds a context's generator behind math.random until a
+// script calls math.seed or the host calls SeedRandom, so runs are
+// reproducible by default.
+const DefaultRandomSeed = 1
+
+// Random returns the generator behind math.random.
+func (c *Context) Random() *rand.Rand {
+	if c.random == nil {
+		c.random = rand.New(rand.NewSource(DefaultRandomSeed))
+	}
+
+	return c.random
+}
+
+// SeedRandom reseeds the generator behind math.random.
+func (c *Context) SeedRandom(seed int64) {
+	c.Random().Seed(seed)
+}
+
+// LoadedModule returns the module previously imported from file.
+func (c *Context) LoadedModule(file string) (*Module, bool) {
+	m, ok := c.modules[file]
+	return m, ok
+}
+
+// BeginImport marks file as being imported, failing if that file is already
+// part of the chain of imports in progress.
+func (c *Context) BeginImport(file string) error {
+	for i, f := range c.importing {
+		if f == file {
+			chain := append(c.importing[i:len(c.importing):len(c.importing)], file)
+			return fmt.Errorf("import cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	c.importing = append(c.importing, file)
+	return nil
+}
+
+// EndImport finishes the import of file begun by BeginImport and caches its
+// module, unless m is nil because loading failed.
+func (c *Context) EndImport(file string, m *Module) {
+	c.importing = c.importing[:len(c.importing)-1]
+	if m == nil {
+		return
+	}
+
+	if c.modules == nil {
+		c.modules = make(map[string]*Module)
+	}
+	c.modules[file] = m
+}
+
+// NewContext creates a context over the given streams. A nil reader is
+// always at end of input and nil writers discard their output.
+func NewContext(stdin io.Reader, stdout, stderr io.Writer) *Context {
+	if stdin == nil {
+		stdin = strings.NewReader("")
+	}
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	return &Context{Stdout: stdout, Stderr: stderr, Stdin: bufio.NewReader(stdin)}
+}
+
+// DefaultContext is used by environments that were not given one, and is
+// wired to the process's standard streams.
+var DefaultContext = NewContext(os.Stdin, os.Stdout, os.Stderr)
+
 type Environment struct {
 	store map[string]Object
 	outer *Environment
+	ctx   *Context
+	file  string
 }
 
 func NewEnclosedEnvironment(outer *Environment) *Environment {
@@ -122,6 +422,52 @@ func NewEnvironment() *Environment {
 	return &Environment{store: s, outer: nil}
 }
 
+// NewEnvironmentWithContext creates a top-level environment whose programs
+// use ctx instead of DefaultContext.
+func NewEnvironmentWithContext(ctx *Context) *Environment {
+	env := NewEnvironment()
+	env.ctx = ctx
+	return env
+}
+
+// NewModuleEnvironment creates the top-level environment for the program in
+// file, which imports inside it are resolved against.
+func NewModuleEnvironment(ctx *Context, file string) *Environment {
+	env := NewEnvironmentWithContext(ctx)
+	env.file = file
+	return env
+}
+
+// File returns the file of the nearest enclosing module environment, or ""
+// outside of any.
+func (e *Environment) File() string {
+	for env := e; env != nil; env = env.outer {
+		if env.file != "" {
+			return env.file
+		}
+	}
+
+	return ""
+}
+
+// Bindings returns the names bound directly in this environment. The map is
+// shared with the environment, so later assignments show through.
+func (e *Environment) Bindings() map[string]Object {
+	return e.store
+}
+
+// Context returns the context of the nearest enclosing environment that has
+// one.
+func (e *Environment) Context() *Context {
+	for env := e; env != nil; env = env.outer {
+		if env.ctx != nil {
+			return env.ctx
+		}
+	}
+
+	return DefaultContext
+}
+
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.store[name]
 	if !ok && e.outer != nil {
@@ -136,6 +482,20 @@ func (e *Environment) Set(name string, val Object) Object {
 	return val
 }
 
+// Assign rebinds name in the nearest environment that already defines it.
+func (e *Environment) Assign(name string, val Object) (Object, bool) {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return val, true
+	}
+
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+
+	return nil, false
+}
+
 type Function struct {
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
@@ -175,16 +535,19 @@ func (s *String) Inspect() string {
 	return `"` + s.Value + `"`
 }
 
+func (s *String) Equal(other Object) bool {
+	o, ok := other.(*String)
+	return ok && o.Value == s.Value
+}
+
 func (s *String) HashKey() HashKey {
-	h := fnv.New64a()
-	h.Write([]byte(s.Value))
 	return HashKey{
-		Type:  s.Type(),
-		Value: h.Sum64(),
+		Type: s.Type(),
+		Text: s.Value,
 	}
 }
 
-type BuiltinFunction func(args ...Object) Object
+type BuiltinFunction func(ctx *Context, args ...Object) Object
 type Builtin struct {
 	Fn BuiltinFunction
 }
@@ -204,11 +567,21 @@ func (a *Array) Type() ObjectType {
 	return ARRAY_OBJ
 }
 func (a *Array) Inspect() string {
+	return a.inspect(map[Object]bool{})
+}
+
+func (a *Array) inspect(seen map[Object]bool) string {
+	if seen[a] {
+		return "[...]"
+	}
+	seen[a] = true
+	defer delete(seen, a)
+
 	var out bytes.Buffer
 
 	elements := []string{}
 	for _, e := range a.Elements {
-		elements = append(elements, e.Inspect())
+		elements = append(elements, inspect(e, seen))
 	}
 
 	out.WriteString("[")
@@ -218,33 +591,582 @@ func (a *Array) Inspect() string {
 	return out.String()
 }
 
+func (a *Array) Equal(other Object) bool {
+	return Equal(a, other)
+}
+
+func (a *Array) equal(other Object, seen map[objectPair]bool) bool {
+	o, ok := other.(*Array)
+	return ok && elementsEqual(a.Elements, o.Elements, seen)
+}
+
+func elementsEqual(a, b []Object, seen map[objectPair]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !equal(a[i], b[i], seen) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Range is a lazy sequence of integers; its elements are computed on demand
+// rather than stored.
+type Range struct {
+	Start     int64
+	End       int64
+	Inclusive bool
+}
+
+func (r *Range) Type() ObjectType {
+	return RANGE_OBJ
+}
+func (r *Range) Inspect() string {
+	if r.Inclusive {
+		return fmt.Sprintf("%d..%d", r.Start, r.End)
+	}
+
+	return fmt.Sprintf("%d..<%d", r.Start, r.End)
+}
+
+// Len returns the number of integers in the range. The evaluator rejects
+// ranges whose length does not fit in an int64.
+func (r *Range) Len() int64 {
+	if r.End < r.Start || r.End == r.Start && !r.Inclusive {
+		return 0
+	}
+
+	if r.Inclusive {
+		return r.End - r.Start + 1
+	}
+
+	return r.End - r.Start
+}
+
+// At returns the i-th integer of the range, without bounds checking.
+func (r *Range) At(i int64) int64 {
+	return r.Start + i
+}
+
+// Equal reports whether both ranges produce the same integers, so 1..3 and
+// 1..<4 are equal.
+func (r *Range) Equal(other Object) bool {
+	o, ok := other.(*Range)
+	if !ok || r.Len() != o.Len() {
+		return false
+	}
+
+	return r.Len() == 0 || r.Start == o.Start
+}
+
+// Tuple is an immutable, fixed-size sequence. A tuple whose elements are
+// all hashable is itself hashable, which lets it key a hash by several
+// values at once.
+type Tuple struct {
+	Elements []Object
+}
+
+func (t *Tuple) Type() ObjectType {
+	return TUPLE_OBJ
+}
+func (t *Tuple) Inspect() string {
+	return t.inspect(map[Object]bool{})
+}
+
+func (t *Tuple) inspect(seen map[Object]bool) string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, e := range t.Elements {
+		elements = append(elements, inspect(e, seen))
+	}
+
+	out.WriteString("(")
+	out.WriteString(strings.Join(elements, ", "))
+	if len(elements) == 1 {
+		out.WriteString(",")
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
+func (t *Tuple) Equal(other Object) bool {
+	return Equal(t, other)
+}
+
+func (t *Tuple) equal(other Object, seen map[objectPair]bool) bool {
+	o, ok := other.(*Tuple)
+	return ok && elementsEqual(t.Elements, o.Elements, seen)
+}
+
+// HashKey encodes the element keys, each length-prefixed, into Text so that
+// structurally equal tuples share a key and different ones never do. Use
+// HashKeyOf to check first that every element is hashable.
+func (t *Tuple) HashKey() HashKey {
+	var out bytes.Buffer
+	for _, e := range t.Elements {
+		k := e.(Hashable).HashKey()
+		fmt.Fprintf(&out, "%s:%d:%d:%s", k.Type, k.Value, len(k.Text), k.Text)
+	}
+
+	return HashKey{
+		Type: t.Type(),
+		Text: out.String(),
+	}
+}
+
+// Module is a named namespace of values, such as a builtin library.
+type Module struct {
+	Name    string
+	Members map[string]Object
+}
+
+func (m *Module) Type() ObjectType {
+	return MODULE_OBJ
+}
+func (m *Module) Inspect() string {
+	return "<module " + m.Name + ">"
+}
+
+// Regex is a compiled regular expression.
+type Regex struct {
+	Value *regexp.Regexp
+}
+
+func (r *Regex) Type() ObjectType {
+	return REGEX_OBJ
+}
+func (r *Regex) Inspect() string {
+	return "/" + r.Value.String() + "/"
+}
+
+func (r *Regex) Equal(other Object) bool {
+	o, ok := other.(*Regex)
+	return ok && r.Value.String() == o.Value.String()
+}
+
+// Time is an instant, printed in RFC 3339 form.
+type Time struct {
+	Value time.Time
+}
+
+func (t *Time) Type() ObjectType {
+	return TIME_OBJ
+}
+func (t *Time) Inspect() string {
+	return t.Value.Format(time.RFC3339Nano)
+}
+
+func (t *Time) Equal(other Object) bool {
+	o, ok := other.(*Time)
+	return ok && t.Value.Equal(o.Value)
+}
+
+// Duration is the elapsed time between two instants, printed like "1h30m0s".
+type Duration struct {
+	Value time.Duration
+}
+
+func (d *Duration) Type() ObjectType {
+	return DURATION_OBJ
+}
+func (d *Duration) Inspect() string {
+	return d.Value.String()
+}
+
+func (d *Duration) Equal(other Object) bool {
+	o, ok := other.(*Duration)
+	return ok && d.Value == o.Value
+}
+
+// Struct is a record type declared with struct. Calling it with one value
+// per field constructs an Instance.
+type Struct struct {
+	Name   string
+	Fields []string
+}
+
+func (s *Struct) Type() ObjectType {
+	return STRUCT_OBJ
+}
+func (s *Struct) Inspect() string {
+	return "<struct " + s.Name + ">"
+}
+
+// FieldIndex returns the position of the named field, or -1.
+func (s *Struct) FieldIndex(name string) int {
+	for i, f := range s.Fields {
+		if f == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Instance is a value of a Struct, holding one value per field in
+// declaration order.
+type Instance struct {
+	Struct *Struct
+	Values []Object
+}
+
+func (i *Instance) Type() ObjectType {
+	return INSTANCE_OBJ
+}
+func (i *Instance) Inspect() string {
+	return i.inspect(map[Object]bool{})
+}
+
+func (i *Instance) inspect(seen map[Object]bool) string {
+	if seen[i] {
+		return i.Struct.Name + "{...}"
+	}
+	seen[i] = true
+	defer delete(seen, i)
+
+	var out bytes.Buffer
+
+	fields := []string{}
+	for idx, name := range i.Struct.Fields {
+		fields = append(fields, name+": "+inspect(i.Values[idx], seen))
+	}
+
+	out.WriteString(i.Struct.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Equal reports whether other is an instance of the same struct with equal
+// field values.
+func (i *Instance) Equal(other Object) bool {
+	return Equal(i, other)
+}
+
+func (i *Instance) equal(other Object, seen map[objectPair]bool) bool {
+	o, ok := other.(*Instance)
+	return ok && i.Struct == o.Struct && elementsEqual(i.Values, o.Values, seen)
+}
+
+// Class is declared with class. Calling it creates an Object and runs the
+// init method, if the class or an ancestor defines one.
+type Class struct {
+	Name       string
+	Superclass *Class
+	Methods    map[string]*Function
+}
+
+func (c *Class) Type() ObjectType {
+	return CLASS_OBJ
+}
+func (c *Class) Inspect() string {
+	return "<class " + c.Name + ">"
+}
+
+// FindMethod looks a method up on the class and then on its ancestors,
+// returning it along with the class that defines it.
+func (c *Class) FindMethod(name string) (*Function, *Class) {
+	for class := c; class != nil; class = class.Superclass {
+		if fn, ok := class.Methods[name]; ok {
+			return fn, class
+		}
+	}
+
+	return nil, nil
+}
+
+// ClassObject is an instance of a Class. Unlike struct instances, fields
+// are created by assigning to them, usually on self in init.
+type ClassObject struct {
+	Class  *Class
+	fields map[string]Object
+	names  []string
+}
+
+func NewClassObject(class *Class) *ClassObject {
+	return &ClassObject{Class: class, fields: make(map[string]Object)}
+}
+
+func (o *ClassObject) Type() ObjectType {
+	return OBJECT_OBJ
+}
+func (o *ClassObject) Inspect() string {
+	return o.inspect(map[Object]bool{})
+}
+
+func (o *ClassObject) inspect(seen map[Object]bool) string {
+	if seen[o] {
+		return o.Class.Name + "{...}"
+	}
+	seen[o] = true
+	defer delete(seen, o)
+
+	fields := []string{}
+	for _, name := range o.names {
+		fields = append(fields, name+": "+inspect(o.fields[name], seen))
+	}
+
+	return o.Class.Name + "{" + strings.Join(fields, ", ") + "}"
+}
+
+func (o *ClassObject) Get(name string) (Object, bool) {
+	val, ok := o.fields[name]
+	return val, ok
+}
+
+// Set assigns a field, remembering the order fields were first set in.
+func (o *ClassObject) Set(name string, val Object) {
+	if _, ok := o.fields[name]; !ok {
+		o.names = append(o.names, name)
+	}
+	o.fields[name] = val
+}
+
+// Super is bound to super inside methods, and finds methods starting from
+// the superclass of the class defining the running method.
+type Super struct {
+	Self  *ClassObject
+	Class *Class
+}
+
+func (s *Super) Type() ObjectType {
+	return SUPER_OBJ
+}
+func (s *Super) Inspect() string {
+	return "<super " + s.Class.Name + ">"
+}
+
+// Enum is declared with enum. Its variants are reached as members, as in
+// Color.Red.
+type Enum struct {
+	Name     string
+	Variants []*Variant
+}
+
+func (e *Enum) Type() ObjectType {
+	return ENUM_OBJ
+}
+func (e *Enum) Inspect() string {
+	return "<enum " + e.Name + ">"
+}
+
+// Variant returns the named variant, or nil.
+func (e *Enum) Variant(name string) *Variant {
+	for _, v := range e.Variants {
+		if v.Name == name {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// Variant is one variant of an Enum. A variant without fields has a single
+// value, Unit; one with fields is called with a value per field to create
+// an EnumValue.
+type Variant struct {
+	Enum   *Enum
+	Name   string
+	Fields []string
+	Unit   *EnumValue
+}
+
+func (v *Variant) Type() ObjectType {
+	return VARIANT_OBJ
+}
+func (v *Variant) Inspect() string {
+	return "<variant " + v.Enum.Name + "." + v.Name + ">"
+}
+
+// FieldIndex returns the position of the named field, or -1.
+func (v *Variant) FieldIndex(name string) int {
+	for i, f := range v.Fields {
+		if f == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// EnumValue is a value of one variant of an Enum, holding one value per
+// field of the variant.
+type EnumValue struct {
+	Variant *Variant
+	Values  []Object
+}
+
+func (ev *EnumValue) Type() ObjectType {
+	return ENUM_VALUE_OBJ
+}
+func (ev *EnumValue) Inspect() string {
+	return ev.inspect(map[Object]bool{})
+}
+
+func (ev *EnumValue) inspect(seen map[Object]bool) string {
+	name := ev.Variant.Enum.Name + "." + ev.Variant.Name
+	if len(ev.Values) == 0 {
+		return name
+	}
+
+	values := []string{}
+	for _, v := range ev.Values {
+		values = append(values, inspect(v, seen))
+	}
+
+	return name + "(" + strings.Join(values, ", ") + ")"
+}
+
+// Equal reports whether other is of the same variant with equal values.
+func (ev *EnumValue) Equal(other Object) bool {
+	return Equal(ev, other)
+}
+
+func (ev *EnumValue) equal(other Object, seen map[objectPair]bool) bool {
+	o, ok := other.(*EnumValue)
+	return ok && ev.Variant == o.Variant && elementsEqual(ev.Values, o.Values, seen)
+}
+
+// Result is either ok(Value) or err(Value), for functions that report
+// failure by returning it rather than raising an error.
+type Result struct {
+	Ok    bool
+	Value Object
+}
+
+func (r *Result) Type() ObjectType {
+	return RESULT_OBJ
+}
+func (r *Result) Inspect() string {
+	return r.inspect(map[Object]bool{})
+}
+
+func (r *Result) inspect(seen map[Object]bool) string {
+	if r.Ok {
+		return "ok(" + inspect(r.Value, seen) + ")"
+	}
+
+	return "err(" + inspect(r.Value, seen) + ")"
+}
+
+func (r *Result) Equal(other Object) bool {
+	return Equal(r, other)
+}
+
+func (r *Result) equal(other Object, seen map[objectPair]bool) bool {
+	o, ok := other.(*Result)
+	return ok && r.Ok == o.Ok && equal(r.Value, o.Value, seen)
+}
+
+// Quote holds unevaluated code, as returned by quote(expr).
+type Quote struct {
+	Node ast.Node
+}
+
+func (q *Quote) Type() ObjectType {
+	return QUOTE_OBJ
+}
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
+
+// Macro is defined by binding a macro literal with a top-level let. Its
+// body runs during macro expansion, on its arguments as quotes.
+type Macro struct {
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (m *Macro) Type() ObjectType {
+	return MACRO_OBJ
+}
+func (m *Macro) Inspect() string {
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+
+	return "macro(" + strings.Join(params, ", ") + ") " + m.Body.String()
+}
+
 type Hashable interface {
 	HashKey() HashKey
 }
 
+// HashKeyOf returns the hash key of obj, reporting false when obj cannot be
+// used as a hash key.
+func HashKeyOf(obj Object) (HashKey, bool) {
+	if t, ok := obj.(*Tuple); ok {
+		for _, e := range t.Elements {
+			if _, ok := HashKeyOf(e); !ok {
+				return HashKey{}, false
+			}
+		}
+	}
+
+	h, ok := obj.(Hashable)
+	if !ok {
+		return HashKey{}, false
+	}
+
+	return h.HashKey(), true
+}
+
 type HashPair struct {
 	Key   Object
 	Value Object
 }
 
+// HashKey identifies a hash entry. It carries the key's exact value rather
+// than a digest of it: integers and booleans fit in Value and strings are
+// kept whole in Text. Map lookups compare every field, so two different keys
+// can never collide while lookups stay O(1).
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
+	Text  string
 }
 
+// Hash keeps its pairs in insertion order so that printing and iteration
+// are reproducible. Mutate it through Set and Delete so the order stays in
+// sync with Pairs.
 type Hash struct {
 	Pairs map[HashKey]HashPair
+	keys  []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
 }
 
 func (h *Hash) Type() ObjectType {
 	return HASH_OBJ
 }
 func (h *Hash) Inspect() string {
+	return h.inspect(map[Object]bool{})
+}
+
+func (h *Hash) inspect(seen map[Object]bool) string {
+	if seen[h] {
+		return "{...}"
+	}
+	seen[h] = true
+	defer delete(seen, h)
+
 	var out bytes.Buffer
 
 	elements := []string{}
-	for _, p := range h.Pairs {
-		elements = append(elements, p.Key.Inspect()+":"+p.Value.Inspect())
+	for _, p := range h.Ordered() {
+		elements = append(elements, p.Key.Inspect()+":"+inspect(p.Value, seen))
 	}
 
 	out.WriteString("{")
@@ -253,3 +1175,71 @@ func (h *Hash) Inspect() string {
 
 	return out.String()
 }
+
+// Equal reports whether both hashes hold equal values under the same keys,
+// regardless of insertion order.
+func (h *Hash) Equal(other Object) bool {
+	return Equal(h, other)
+}
+
+func (h *Hash) equal(other Object, seen map[objectPair]bool) bool {
+	o, ok := other.(*Hash)
+	if !ok || h.Len() != o.Len() {
+		return false
+	}
+
+	for key, pair := range h.Pairs {
+		otherPair, ok := o.Get(key)
+		if !ok || !equal(pair.Value, otherPair.Value, seen) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (h *Hash) Get(key HashKey) (HashPair, bool) {
+	pair, ok := h.Pairs[key]
+	return pair, ok
+}
+
+// Set stores pair under key. A key that is already present keeps its
+// original position.
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	if _, ok := h.Pairs[key]; !ok {
+		h.keys = append(h.keys, key)
+	}
+
+	h.Pairs[key] = pair
+}
+
+// Delete removes key and reports whether it was present.
+func (h *Hash) Delete(key HashKey) bool {
+	if _, ok := h.Pairs[key]; !ok {
+		return false
+	}
+
+	delete(h.Pairs, key)
+	for i, k := range h.keys {
+		if k == key {
+			h.keys = append(h.keys[:i], h.keys[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+func (h *Hash) Len() int {
+	return len(h.Pairs)
+}
+
+// Ordered returns the pairs in insertion order.
+func (h *Hash) Ordered() []HashPair {
+	pairs := make([]HashPair, 0, len(h.keys))
+	for _, k := range h.keys {
+		pairs = append(pairs, h.Pairs[k])
+	}
+
+	return pairs
+}
@@ -13,8 +13,10 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // = or +=
 	EQUALS      // ==
 	LESSGREATER // > or <
+	RANGE       // 1..10
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -x or !x
@@ -23,16 +25,26 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.RANGE:           RANGE,
+	token.RANGE_EXCLUSIVE: RANGE,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
+	token.DOT:             INDEX,
+	token.OPTIONAL_DOT:    INDEX,
+	token.QUESTION:        INDEX,
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -118,6 +130,36 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	valid := false
+	switch target := target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+		valid = true
+	case *ast.MemberExpression:
+		// a?.b = c has nothing sensible to do when a is null
+		valid = !target.Optional
+	}
+
+	if !valid {
+		msg := fmt.Sprintf("invalid assignment target %s", target.String())
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	expression := &ast.AssignExpression{
+		Token:    p.curToken,
+		Target:   target,
+		Operator: p.curToken.Literal,
+	}
+
+	p.nextToken()
+
+	// assignment is right associative, so a = b = c assigns c to both
+	expression.Value = p.parseExpression(ASSIGN - 1)
+
+	return expression
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{
 		Token:    p.curToken,
@@ -144,6 +186,19 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokenType] = fn
 }
@@ -161,6 +216,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
@@ -168,8 +224,13 @@ func New(l *lexer.Lexer) *Parser {
 
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
 	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.WHILE, p.parseWhileExpression)
+	p.registerPrefix(token.FOR, p.parseForExpression)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
 
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
@@ -177,6 +238,9 @@ func New(l *lexer.Lexer) *Parser {
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
+	p.registerInfix(token.OPTIONAL_DOT, p.parseMemberExpression)
+	p.registerInfix(token.QUESTION, p.parsePropagateExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -187,6 +251,14 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.RANGE, p.parseInfixExpression)
+	p.registerInfix(token.RANGE_EXCLUSIVE, p.parseInfixExpression)
+
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
 
 	p.nextToken()
 	p.nextToken()
@@ -212,6 +284,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		v := p.parseExpression(LOWEST)
 
 		hash.Pairs[k] = v
+		hash.Keys = append(hash.Keys, k)
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -225,13 +298,62 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	return hash
 }
 
+func (p *Parser) parsePropagateExpression(left ast.Expression) ast.Expression {
+	return &ast.PropagateExpression{Token: p.curToken, Value: left}
+}
+
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{
+		Token:    p.curToken,
+		Left:     left,
+		Optional: p.curTokenIs(token.OPTIONAL_DOT),
+	}
+
+	// keywords are fine as member names, as in regex.match
+	if token.IsKeyword(p.peekToken.Type) {
+		p.nextToken()
+	} else if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	exp.Member = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	return exp
+}
+
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{
-		Token: p.curToken,
+	tok := p.curToken
+	p.nextToken()
+
+	var start ast.Expression
+	if !p.curTokenIs(token.COLON) {
+		start = p.parseExpression(LOWEST)
+		if !p.peekTokenIs(token.COLON) {
+			exp := &ast.IndexExpression{
+				Token: tok,
+				Left:  left,
+				Index: start,
+			}
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+
+			return exp
+		}
+
+		p.nextToken()
+	}
+
+	exp := &ast.SliceExpression{
+		Token: tok,
 		Left:  left,
+		Start: start,
 	}
-	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.End = p.parseExpression(LOWEST)
+	}
+
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
@@ -292,6 +414,25 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	exp := &ast.MacroLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	exp.Parameters = p.parseFunctionParameters()
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	exp.Body = p.parseBlockStatement()
+
+	return exp
+}
+
 func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 
 	var idents []*ast.Identifier
@@ -346,6 +487,68 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseWhileExpression() ast.Expression {
+	exp := &ast.WhileExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	exp.Condition = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	exp.Body = p.parseBlockStatement()
+
+	return exp
+}
+
+func (p *Parser) parseForExpression() ast.Expression {
+	exp := &ast.ForExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	exp.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		exp.Key = exp.Value
+		exp.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	exp.Iterable = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	exp.Body = p.parseBlockStatement()
+
+	return exp
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	bs := &ast.BlockStatement{}
 	bs.Token = p.curToken
@@ -363,11 +566,39 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	return bs
 }
 
+// parseGroupedExpression parses (x) as x, and (), (x,) and (x, y, ...) as
+// tuple literals.
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	tok := p.curToken
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return &ast.TupleLiteral{Token: tok, Elements: []ast.Expression{}}
+	}
+
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
 
+	if p.peekTokenIs(token.COMMA) {
+		tuple := &ast.TupleLiteral{Token: tok, Elements: []ast.Expression{exp}}
+		for p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+			if p.peekTokenIs(token.RPAREN) {
+				break
+			}
+
+			p.nextToken()
+			tuple.Elements = append(tuple.Elements, p.parseExpression(LOWEST))
+		}
+
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+
+		return tuple
+	}
+
 	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
@@ -453,6 +684,20 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
+	case token.CLASS:
+		return p.parseClassStatement()
+	case token.ENUM:
+		return p.parseEnumStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -469,20 +714,186 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 		stmt.ReturnValue = p.parseExpression(LOWEST)
 	}
 
-	if !p.expectPeek(token.SEMICOLON) {
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
 		return nil
 	}
 
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
 	return stmt
 }
 
-func (p *Parser) parseLetStatement() *ast.LetStatement {
-	stmt := &ast.LetStatement{Token: p.curToken}
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{Token: p.curToken}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+
+	stmt.Path = p.curToken.Literal
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	stmt := &ast.StructStatement{Token: p.curToken}
 	if !p.expectPeek(token.IDENT) {
 		return nil
 	}
 
 	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		field := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[field.Value] {
+			msg := fmt.Sprintf("duplicate field %s in struct %s", field.Value, stmt.Name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		seen[field.Value] = true
+		stmt.Fields = append(stmt.Fields, field)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseClassStatement() *ast.ClassStatement {
+	stmt := &ast.ClassStatement{Token: p.curToken}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.peekTokenIs(token.EXTENDS) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Superclass = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		method := &ast.ClassMethod{
+			Name:     &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+			Function: &ast.FunctionLiteral{Token: p.curToken},
+		}
+		if seen[method.Name.Value] {
+			msg := fmt.Sprintf("duplicate method %s in class %s", method.Name.Value, stmt.Name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		seen[method.Name.Value] = true
+
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+
+		p.nextToken()
+		method.Function.Parameters = p.parseFunctionParameters()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		method.Function.Body = p.parseBlockStatement()
+		stmt.Methods = append(stmt.Methods, method)
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{Token: p.curToken}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseLetStatement() *ast.LetStatement {
+	stmt := &ast.LetStatement{Token: p.curToken}
+
+	switch p.peekToken.Type {
+	case token.LBRACKET:
+		p.nextToken()
+		stmt.Pattern = p.parseArrayPattern()
+	case token.LBRACE:
+		p.nextToken()
+		stmt.Pattern = p.parseHashPattern()
+	default:
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if stmt.Name == nil && stmt.Pattern == nil {
+		return nil
+	}
+
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
@@ -514,3 +925,324 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 	p.peekError(t)
 	return false
 }
+
+func (p *Parser) parseEnumStatement() *ast.EnumStatement {
+	stmt := &ast.EnumStatement{Token: p.curToken}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		variant := &ast.EnumVariant{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+		if seen[variant.Name.Value] {
+			msg := fmt.Sprintf("duplicate variant %s in enum %s", variant.Name.Value, stmt.Name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		seen[variant.Name.Value] = true
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			p.nextToken()
+			variant.Fields = p.parseFunctionParameters()
+			if !p.curTokenIs(token.RPAREN) {
+				return nil
+			}
+		}
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	exp.Subject = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		exp.Arms = append(exp.Arms, arm)
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return exp
+}
+
+// parseMatchArm parses pattern [if guard] => body. A body in braces is a
+// block, so an arm producing a hash literal has to wrap it in parentheses.
+// Arms are separated by commas, which are optional after a block.
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{Pattern: p.parsePattern()}
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+
+	p.nextToken()
+	if p.curTokenIs(token.LBRACE) {
+		arm.Body = p.parseBlockStatement()
+		if p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+		}
+		return arm
+	}
+
+	stmt := &ast.ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+	arm.Body = &ast.BlockStatement{Token: stmt.Token, Statements: []ast.Statement{stmt}}
+
+	if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+		return nil
+	}
+
+	return arm
+}
+
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.IDENT:
+		if p.curToken.Literal == "_" {
+			return &ast.WildcardPattern{Token: p.curToken}
+		}
+
+		if p.peekTokenIs(token.DOT) {
+			return p.parseVariantPattern()
+		}
+
+		return &ast.BindingPattern{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE:
+		return &ast.LiteralPattern{Token: p.curToken, Value: p.prefixParseFns[p.curToken.Type]()}
+
+	case token.MINUS:
+		if !p.peekTokenIs(token.INT) && !p.peekTokenIs(token.FLOAT) {
+			p.peekError(token.INT)
+			return nil
+		}
+
+		return &ast.LiteralPattern{Token: p.curToken, Value: p.parsePrefixExpression()}
+
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+
+	case token.LBRACE:
+		return p.parseHashPattern()
+
+	default:
+		msg := fmt.Sprintf("unexpected %s in pattern", p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+}
+
+func (p *Parser) parseVariantPattern() ast.Pattern {
+	pattern := &ast.VariantPattern{
+		Token: p.curToken,
+		Enum:  &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+	}
+
+	p.nextToken()
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	pattern.Variant = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.peekTokenIs(token.LPAREN) {
+		return pattern
+	}
+
+	p.nextToken()
+	pattern.Args = []ast.Pattern{}
+	for !p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		arg := p.parsePattern()
+		if arg == nil {
+			return nil
+		}
+		pattern.Args = append(pattern.Args, arg)
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+
+			pattern.Rest = p.parsePattern()
+			if !p.peekTokenIs(token.RBRACKET) {
+				msg := fmt.Sprintf("rest element must come last, got %s", p.peekToken.Type)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			break
+		}
+
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+// parseHashPattern parses {key: pattern, ...}, where keys are names or
+// strings and a bare name both names the key and binds its value.
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		if !p.curTokenIs(token.IDENT) && !p.curTokenIs(token.STRING) {
+			msg := fmt.Sprintf("unexpected %s in hash pattern", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		key := p.curToken
+		var value ast.Pattern
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			value = p.parsePattern()
+			if value == nil {
+				return nil
+			}
+		} else if key.Type == token.IDENT {
+			value = &ast.BindingPattern{Name: &ast.Identifier{Token: key, Value: key.Literal}}
+		} else if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		pattern.Keys = append(pattern.Keys, key.Literal)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	exp := &ast.TryExpression{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	exp.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			exp.Param = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if !p.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		exp.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		exp.Finally = p.parseBlockStatement()
+	}
+
+	if exp.Catch == nil && exp.Finally == nil {
+		p.errors = append(p.errors, "try without catch or finally")
+		return nil
+	}
+
+	return exp
+}
@@ -391,6 +391,18 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a + b + c * d / f + g)",
 			"add((((a + b) + ((c * d) / f)) + g))",
 		},
+		{
+			"1..n + 1",
+			"(1 .. (n + 1))",
+		},
+		{
+			"a..<b == c",
+			"((a ..< b) == c)",
+		},
+		{
+			"x < 1..2",
+			"(x < (1 .. 2))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -870,3 +882,677 @@ func TestHashLiteral(t *testing.T) {
 		testIntegerLiteral(t, v, expectedValue)
 	}
 }
+
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 5", "x = 5"},
+		{"arr[1] = 2 * 3", "arr[1] = (2 * 3)"},
+		{`h["a"] += 1`, `h[a] += 1`},
+		{"a[0] -= b[1]", "a[0] -= b[1]"},
+		{"a = b = c", "a = b = c"},
+		{"x *= y + z", "x *= (y + z)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+		}
+
+		if _, ok := stmt.Expression.(*ast.AssignExpression); !ok {
+			t.Fatalf("stmt.Expression is not *ast.AssignExpression, got=%T", stmt.Expression)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestInvalidAssignTarget(t *testing.T) {
+	l := lexer.New("1 + 2 = 3")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 {
+		t.Fatalf("expected parser errors, got none")
+	}
+
+	if errors[0] != "invalid assignment target (1 + 2)" {
+		t.Errorf("wrong error message, got=%q", errors[0])
+	}
+}
+
+func TestWhileExpression(t *testing.T) {
+	input := `while (x < 10) { x += 1; break; continue }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.WhileExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.WhileExpression, got=%T", stmt.Expression)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", 10) {
+		return
+	}
+
+	if len(exp.Body.Statements) != 3 {
+		t.Fatalf("body does not contain 3 statements, got=%d", len(exp.Body.Statements))
+	}
+
+	if _, ok := exp.Body.Statements[1].(*ast.BreakStatement); !ok {
+		t.Errorf("body.Statements[1] is not *ast.BreakStatement, got=%T", exp.Body.Statements[1])
+	}
+
+	if _, ok := exp.Body.Statements[2].(*ast.ContinueStatement); !ok {
+		t.Errorf("body.Statements[2] is not *ast.ContinueStatement, got=%T", exp.Body.Statements[2])
+	}
+}
+
+func TestForExpression(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedKey   string
+		expectedValue string
+	}{
+		{`for (x in arr) { puts(x) }`, "", "x"},
+		{`for (k, v in {"a": 1}) { puts(k, v) }`, "k", "v"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.ForExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.ForExpression, got=%T", stmt.Expression)
+		}
+
+		if tt.expectedKey == "" {
+			if exp.Key != nil {
+				t.Errorf("exp.Key is not nil, got=%s", exp.Key)
+			}
+		} else if !testIdentifier(t, exp.Key, tt.expectedKey) {
+			return
+		}
+
+		if !testIdentifier(t, exp.Value, tt.expectedValue) {
+			return
+		}
+
+		if len(exp.Body.Statements) != 1 {
+			t.Errorf("body does not contain 1 statement, got=%d", len(exp.Body.Statements))
+		}
+	}
+}
+
+func TestSliceExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"arr[1:3]", "arr[1:3]"},
+		{"s[:5]", "s[:5]"},
+		{"arr[-2:]", "arr[(-2):]"},
+		{"arr[:]", "arr[:]"},
+		{"arr[i + 1:len(arr)]", "arr[(i + 1):len(arr)]"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+		}
+
+		if _, ok := stmt.Expression.(*ast.SliceExpression); !ok {
+			t.Fatalf("stmt.Expression is not *ast.SliceExpression, got=%T", stmt.Expression)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestHashLiteralKeepsSourceOrder(t *testing.T) {
+	input := `{"c": 1, "a": 2, "b": 3, "d": 4, "e": 5}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	expected := "{c:1, a:2, b:3, d:4, e:5}"
+	for i := 0; i < 10; i++ {
+		if program.String() != expected {
+			t.Fatalf("exprected=%q, got=%q", expected, program.String())
+		}
+	}
+}
+
+func TestTupleLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		length   int
+	}{
+		{"(1, 2)", "(1, 2)", 2},
+		{"(a, b + c, d)", "(a, (b + c), d)", 3},
+		{"(1,)", "(1,)", 1},
+		{"()", "()", 0},
+		{"(1, (2, 3))", "(1, (2, 3))", 2},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+		}
+
+		tuple, ok := stmt.Expression.(*ast.TupleLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.TupleLiteral, got=%T", stmt.Expression)
+		}
+
+		if len(tuple.Elements) != tt.length {
+			t.Errorf("tuple has wrong num of elements, got=%d, want=%d", len(tuple.Elements), tt.length)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	input := `3.25;`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statement[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.FloatLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.FloatLiteral. got=%T", stmt.Expression)
+	}
+
+	if literal.Value != 3.25 {
+		t.Errorf("literal.Value not %f. got=%f", 3.25, literal.Value)
+	}
+
+	if literal.TokenLiteral() != "3.25" {
+		t.Errorf("literal.TokenLiteral not %s. got=%s", "3.25", literal.TokenLiteral())
+	}
+}
+
+func TestImportStatement(t *testing.T) {
+	input := `import "lib/util"; import "math_ext"`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements, got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ImportStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ImportStatement, got=%T", program.Statements[0])
+	}
+
+	if stmt.Path != "lib/util" {
+		t.Errorf("stmt.Path is not %q, got=%q", "lib/util", stmt.Path)
+	}
+
+	if program.String() != `import "lib/util";import "math_ext";` {
+		t.Errorf("program.String() wrong, got=%q", program.String())
+	}
+}
+
+func TestImportStatementRequiresString(t *testing.T) {
+	l := lexer.New("import util;")
+	p := New(l)
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected a parser error for a non-string import path")
+	}
+}
+
+func TestMemberExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"util.name", "util.name"},
+		{"a.b.c", "a.b.c"},
+		{"math.sqrt(2)", "math.sqrt(2)"},
+		{"-lib.x * 2", "((-lib.x) * 2)"},
+		{"lib.items[0]", "lib.items[0]"},
+		{"a?.b.c", "a?.b.c"},
+		{"config.db.port = 5432", "config.db.port = 5432"},
+		{"counts.total += 1", "counts.total += 1"},
+		{"regex.match(p, s)", "regex.match(p, s)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestMemberNameMustBeIdentifier(t *testing.T) {
+	l := lexer.New(`regex."match"`)
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "expected next token to be IDENT, got STRING instead" {
+		t.Fatalf("expected a member name error, got=%v", errors)
+	}
+}
+
+func TestOptionalMemberIsNotAssignable(t *testing.T) {
+	l := lexer.New("a?.b = 1")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "invalid assignment target a?.b" {
+		t.Fatalf("expected invalid assignment target error, got=%v", errors)
+	}
+}
+
+func TestStructStatement(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedName   string
+		expectedFields []string
+	}{
+		{"struct Point { x, y }", "Point", []string{"x", "y"}},
+		{"struct Empty {};", "Empty", nil},
+		{"struct User { name, age, }", "User", []string{"name", "age"}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.StructStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.StructStatement, got=%T", program.Statements[0])
+		}
+
+		if stmt.Name.Value != tt.expectedName {
+			t.Errorf("stmt.Name is not %q, got=%q", tt.expectedName, stmt.Name.Value)
+		}
+
+		if len(stmt.Fields) != len(tt.expectedFields) {
+			t.Fatalf("wrong number of fields, want=%d, got=%d", len(tt.expectedFields), len(stmt.Fields))
+		}
+
+		for i, f := range tt.expectedFields {
+			testIdentifier(t, stmt.Fields[i], f)
+		}
+	}
+}
+
+func TestStructStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct Point { x, x }", "duplicate field x in struct Point"},
+		{"struct { x }", "expected next token to be IDENT, got { instead"},
+		{"struct P { 1 }", "expected next token to be IDENT, got INT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q, want=%q, got=%v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestClassStatement(t *testing.T) {
+	input := `
+class Dog extends Animal {
+	init(name) { self.name = name }
+	speak() { "woof" }
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ClassStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ClassStatement, got=%T", program.Statements[0])
+	}
+
+	testIdentifier(t, stmt.Name, "Dog")
+	testIdentifier(t, stmt.Superclass, "Animal")
+
+	if len(stmt.Methods) != 2 {
+		t.Fatalf("wrong number of methods, want=2, got=%d", len(stmt.Methods))
+	}
+
+	testIdentifier(t, stmt.Methods[0].Name, "init")
+	testIdentifier(t, stmt.Methods[0].Function.Parameters[0], "name")
+	testIdentifier(t, stmt.Methods[1].Name, "speak")
+
+	expected := `class Dog extends Animal { init(name) self.name = name speak() woof }`
+	if program.String() != expected {
+		t.Errorf("exprected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestClassStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"class A { f() {} f() {} }", "duplicate method f in class A"},
+		{"class A extends { }", "expected next token to be IDENT, got { instead"},
+		{"class A { f }", "expected next token to be (, got } instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q, want=%q, got=%v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestEnumStatement(t *testing.T) {
+	input := "enum Shape { Circle(r), Rect(w, h), Empty, }"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.EnumStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.EnumStatement, got=%T", program.Statements[0])
+	}
+
+	testIdentifier(t, stmt.Name, "Shape")
+	if len(stmt.Variants) != 3 {
+		t.Fatalf("wrong number of variants, want=3, got=%d", len(stmt.Variants))
+	}
+
+	expected := "enum Shape { Circle(r), Rect(w, h), Empty }"
+	if program.String() != expected {
+		t.Errorf("exprected=%q, got=%q", expected, program.String())
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 => a, _ => b }", "match (x) { 1 => a, _ => b }"},
+		{"match (x) { -1 => a, 2.5 => b, \"s\" => c, true => d }", "match (x) { (-1) => a, 2.5 => b, s => c, true => d }"},
+		{"match (x) { n if n > 0 => n, n => { -n } }", "match (x) { n if (n > 0) => n, n => (-n) }"},
+		{"match (x) { [a, _, ...rest] => rest, [] => 0 }", "match (x) { [a, _, ...rest] => rest, [] => 0 }"},
+		{"match (x) { {name, \"age\": 30} => name }", "match (x) { {name: name, age: 30} => name }"},
+		{"match (x) { Shape.Rect(w, h) => w * h, Shape.Circle => 0, Shape.Empty() => 1 }", "match (x) { Shape.Rect(w, h) => (w * h), Shape.Circle => 0, Shape.Empty() => 1 }"},
+		{"match (x) { 1 => { a } 2 => b }", "match (x) { 1 => a, 2 => b }"},
+		{"re.match(s)", "re.match(s)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestPatternErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enum A { X, X }", "duplicate variant X in enum A"},
+		{"match (x) { [...a, b] => 1 }", "rest element must come last, got ,"},
+		{"match (x) { (a) => 1 }", "unexpected ( in pattern"},
+		{"match (x) { {1: a} => 1 }", "unexpected INT in hash pattern"},
+		{"match (x) { {\"a\"} => 1 }", "expected next token to be :, got } instead"},
+		{"match (x) { -a => 1 }", "expected next token to be INT, got IDENT instead"},
+		{"match (x) { 1 => a 2 => b }", "expected next token to be ,, got INT instead"},
+		{"match (x) { 1 a }", "expected next token to be =>, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q, want=%q, got=%v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = pair;", "let [a, b] = pair;"},
+		{"let [head, ...tail] = [1, 2, 3];", "let [head, ...tail] = [1, 2, 3];"},
+		{"let [_, [x, y]] = p;", "let [_, [x, y]] = p;"},
+		{"let {name, age: years} = person;", "let {name: name, age: years} = person;"},
+		{`let {"full name": n, tags: [first, ..._]} = h;`, "let {full name: n, tags: [first, ..._]} = h;"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.LetStatement, got=%T", program.Statements[0])
+		}
+		if stmt.Pattern == nil {
+			t.Errorf("stmt.Pattern is nil for %q", tt.input)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { f() } catch (e) { g(e) }", "try f() catch (e) g(e)"},
+		{"try { f() } catch { 0 }", "try f() catch 0"},
+		{"try { f() } finally { done() }", "try f() finally done()"},
+		{"let x = try { f() } catch (e) { 1 } finally { 2 };", "let x = try f() catch (e) 1 finally 2;"},
+		{`throw "bad";`, `throw bad;`},
+		{"throw error(m, k)", "throw error(m, k);"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestTryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { f() }", "try without catch or finally"},
+		{"try f()", "expected next token to be {, got IDENT instead"},
+		{"try { f() } catch (1) { }", "expected next token to be IDENT, got INT instead"},
+		{"try { f() } catch (e { }", "expected next token to be ), got { instead"},
+		{"throw;", "no prefix parse function for ; found"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q, want=%q, got=%v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestPropagateExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(x)?", "(f(x)?)"},
+		{"a + b?", "(a + (b?))"},
+		{"-x?", "(-(x?))"},
+		{"a.b(c)?", "(a.b(c)?)"},
+		{"f()??", "((f()?)?)"},
+		{"let v = parse(s)?;", "let v = (parse(s)?);"},
+		{"(f()?).x", "(f()?).x"},
+		{"a?.b", "a?.b"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("exprected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestMacroLiteralParsing(t *testing.T) {
+	input := `macro(x, y) { x + y; }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MacroLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("macro literal parameters wrong. want 2, got=%d", len(macro.Parameters))
+	}
+
+	testLiteralExpression(t, macro.Parameters[0], "x")
+	testLiteralExpression(t, macro.Parameters[1], "y")
+
+	if len(macro.Body.Statements) != 1 {
+		t.Fatalf("macro.Body.Statements has not 1 statements. got=%d", len(macro.Body.Statements))
+	}
+
+	bodyStmt, ok := macro.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("macro body stmt is not ast.ExpressionStatement. got=%T", macro.Body.Statements[0])
+	}
+
+	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
+}
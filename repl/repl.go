@@ -1,11 +1,11 @@
 package repl
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"strings"
 
 	"github.com/elsonwu/monkey-go/evaluator"
 	"github.com/elsonwu/monkey-go/lexer"
@@ -16,8 +16,13 @@ import (
 
 const PROMPT = ">> "
 
+// StartWithoutInteraction evaluates all of in as one program. Script output
+// goes to out, and since in holds the program, input() sees no further
+// lines.
 func StartWithoutInteraction(in io.Reader, out io.Writer) {
-	env := object.NewEnvironment()
+	ctx := object.NewContext(nil, out, out)
+	env := object.NewEnvironmentWithContext(ctx)
+	macroEnv := object.NewEnvironmentWithContext(ctx)
 
 	code, err := ioutil.ReadAll(in)
 	if err != nil {
@@ -32,7 +37,15 @@ func StartWithoutInteraction(in io.Reader, out io.Writer) {
 		return
 	}
 
-	evaluated := evaluator.Eval(program, env)
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, expandErr := evaluator.ExpandMacros(program, macroEnv)
+	if expandErr != nil {
+		io.WriteString(out, expandErr.Inspect())
+		io.WriteString(out, "\n")
+		return
+	}
+
+	evaluated := evaluator.Eval(expanded, env)
 	if evaluated != nil {
 		io.WriteString(out, evaluated.Inspect())
 		io.WriteString(out, "\n")
@@ -41,17 +54,21 @@ func StartWithoutInteraction(in io.Reader, out io.Writer) {
 	}
 }
 
+// Start runs the interactive loop. Lines for input() and readline() are
+// read from in between prompts, and all output goes to out.
 func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+	ctx := object.NewContext(in, out, out)
+	env := object.NewEnvironmentWithContext(ctx)
+	macroEnv := object.NewEnvironmentWithContext(ctx)
 
 	for {
-		fmt.Print(PROMPT)
-		if !scanner.Scan() {
+		io.WriteString(out, PROMPT)
+		line, err := ctx.Stdin.ReadString('\n')
+		if err != nil && line == "" {
 			return
 		}
 
-		line := scanner.Text()
+		line = strings.TrimRight(line, "\r\n")
 		l := lexer.New(line)
 		p := parser.New(l)
 		program := p.ParseProgram()
@@ -61,7 +78,15 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		evaluated := evaluator.Eval(program, env)
+		evaluator.DefineMacros(program, macroEnv)
+		expanded, expandErr := evaluator.ExpandMacros(program, macroEnv)
+		if expandErr != nil {
+			io.WriteString(out, expandErr.Inspect())
+			io.WriteString(out, "\n")
+			continue
+		}
+
+		evaluated := evaluator.Eval(expanded, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
@@ -13,6 +13,7 @@ const (
 
 	IDENT TokenType = "IDENT"
 	INT   TokenType = "INT"
+	FLOAT TokenType = "FLOAT"
 
 	// Operatiors
 	ASSIGN   TokenType = "="
@@ -22,6 +23,19 @@ const (
 	ASTERISK TokenType = "*"
 	SLASH    TokenType = "/"
 
+	PLUS_ASSIGN     TokenType = "+="
+	MINUS_ASSIGN    TokenType = "-="
+	ASTERISK_ASSIGN TokenType = "*="
+	SLASH_ASSIGN    TokenType = "/="
+
+	DOT             TokenType = "."
+	OPTIONAL_DOT    TokenType = "?."
+	QUESTION        TokenType = "?"
+	RANGE           TokenType = ".."
+	RANGE_EXCLUSIVE TokenType = "..<"
+	ELLIPSIS        TokenType = "..."
+	ARROW           TokenType = "=>"
+
 	LT     TokenType = "<"
 	GT     TokenType = ">"
 	EQ     TokenType = "=="
@@ -45,17 +59,60 @@ const (
 	IF       TokenType = "IF"
 	ELSE     TokenType = "ELSE"
 	RETURN   TokenType = "RETURN"
+	WHILE    TokenType = "WHILE"
+	FOR      TokenType = "FOR"
+	IN       TokenType = "IN"
+	BREAK    TokenType = "BREAK"
+	CONTINUE TokenType = "CONTINUE"
+	IMPORT   TokenType = "IMPORT"
+	STRUCT   TokenType = "STRUCT"
+	CLASS    TokenType = "CLASS"
+	EXTENDS  TokenType = "EXTENDS"
+	ENUM     TokenType = "ENUM"
+	MATCH    TokenType = "MATCH"
+	TRY      TokenType = "TRY"
+	CATCH    TokenType = "CATCH"
+	FINALLY  TokenType = "FINALLY"
+	THROW    TokenType = "THROW"
+	MACRO    TokenType = "MACRO"
 	STRING   TokenType = "STRING"
 )
 
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"import":   IMPORT,
+	"struct":   STRUCT,
+	"class":    CLASS,
+	"extends":  EXTENDS,
+	"enum":     ENUM,
+	"match":    MATCH,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
+	"macro":    MACRO,
+}
+
+// IsKeyword reports whether t is the type of a keyword token.
+func IsKeyword(t TokenType) bool {
+	for _, kw := range keywords {
+		if kw == t {
+			return true
+		}
+	}
+
+	return false
 }
 
 func LookupIdent(ident string) TokenType {